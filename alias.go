@@ -0,0 +1,93 @@
+package goe
+
+import (
+	"context"
+	"reflect"
+)
+
+// aliasKey identifies a aliased table of a scope
+type aliasKey struct {
+	table uintptr
+	alias string
+}
+
+// Alias returns a copy of table referenced on the queries by alias, used to join the same
+// table more than once or to join a table with itself. The fields of the returned table
+// can be used on select, join, where and order as the fields of table, by the queries
+// made with the returned context.
+//
+// Calling Alias with the same table and alias on a context returned by Alias returns the same copy.
+// Aliases need a driver that supports [enum.AliasFeature].
+//
+// # Example
+//
+//	ctx, sender := goe.Alias(context.Background(), db.User, "sender")
+//	ctx, receiver := goe.Alias(ctx, db.User, "receiver")
+//
+//	// SELECT messages.text, sender.name, receiver.name FROM messages
+//	// JOIN users sender ON (sender.id = messages.sender_id) JOIN users receiver ON (receiver.id = messages.receiver_id)
+//	rows, err := goe.SelectContext[struct{ Text, Sender, Receiver string }](ctx, &db.Message.Text, &sender.Name, &receiver.Name).
+//		Join(&db.Message.SenderId, &sender.Id).
+//		Join(&db.Message.ReceiverId, &receiver.Id).AsSlice()
+func Alias[T any](ctx context.Context, table *T, alias string) (context.Context, *T) {
+	valueOf := reflect.ValueOf(table).Elem()
+	if alias == "" || valueOf.Kind() != reflect.Struct {
+		panic("goe: invalid alias. try sending a pointer to a table of the database and a non-empty alias")
+	}
+
+	key := aliasKey{table: uintptr(valueOf.Addr().UnsafePointer()), alias: alias}
+	for s := scopeOf(ctx); s != nil; s = s.parent {
+		if s.alias == key {
+			return ctx, s.table.(*T)
+		}
+	}
+
+	addrMap := loadFields()
+	var db *DB
+	for _, fieldOf := range fieldsOf(valueOf) {
+		if f := addrMap.field(uintptr(fieldOf.Addr().UnsafePointer())); f != nil {
+			db = f.getDb()
+			break
+		}
+	}
+	if db == nil {
+		panic("goe: invalid alias. try sending a pointer to a table of the database and a non-empty alias")
+	}
+
+	tableId := scopeOf(ctx).nextId()
+	name := db.driver.KeywordHandler(alias)
+	aliased := reflect.New(valueOf.Type())
+	fields := make(map[uintptr]field)
+	for structField, fieldOf := range indexedFieldsOf(valueOf) {
+		f := db.fields[uintptr(fieldOf.Addr().UnsafePointer())]
+		if f == nil {
+			continue
+		}
+		fields[uintptr(aliased.Elem().FieldByIndex(structField.Index).Addr().UnsafePointer())] = aliasField(f, name, tableId)
+	}
+	return withScope(ctx, aliased.Interface(), key, fields), aliased.Interface().(*T)
+}
+
+// aliasField returns a copy of f referencing the table by alias
+func aliasField(f field, alias string, tableId int) field {
+	switch f := f.(type) {
+	case pk:
+		f.attributeStrings = f.aliased(alias, tableId)
+		return f
+	case att:
+		f.attributeStrings = f.aliased(alias, tableId)
+		return f
+	case manyToOne:
+		f.attributeStrings = f.aliased(alias, tableId)
+		return f
+	case oneToOne:
+		f.attributeStrings = f.aliased(alias, tableId)
+		return f
+	}
+	return f
+}
+
+func (a attributeStrings) aliased(alias string, tableId int) attributeStrings {
+	a.sourceName, a.tableName, a.tableId = a.tableName, alias, tableId
+	return a
+}
@@ -0,0 +1,43 @@
+package goe
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	db, d := openTest(t, false)
+
+	ctx, home := Alias(context.Background(), db.Habitat, "home")
+	_, err := SelectContext[struct{ Animal, Habitat string }](ctx, &db.Animal.Name, &home.Name).
+		Join(&db.Animal.HabitatId, &home.Id).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected alias, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	if len(q.Joins) != 1 || q.Joins[0].Table.Alias != `"home"` || q.Joins[0].Table.Name != `"habitats"` {
+		t.Fatalf("Expected a join with habitats as home, got %+v", q.Joins)
+	}
+	if q.Attributes[1].Table != `"home"` {
+		t.Errorf("Expected the name of home, got %+v", q.Attributes[1])
+	}
+
+	if _, again := Alias(ctx, db.Habitat, "home"); again != home {
+		t.Error("Expected the same table for the same alias on the context")
+	}
+	if _, other := Alias(context.Background(), db.Habitat, "home"); other == home {
+		t.Error("Expected a new table for the alias on other context")
+	}
+}
+
+func TestAliasBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	ctx, home := Alias(context.Background(), db.Habitat, "home")
+	_, err := SelectContext[struct{ Animal, Habitat string }](ctx, &db.Animal.Name, &home.Name).
+		Join(&db.Animal.HabitatId, &home.Id).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -2,10 +2,10 @@ package goe
 
 import (
 	"reflect"
+	"slices"
 
 	"github.com/go-goe/goe/enum"
 	"github.com/go-goe/goe/model"
-	"github.com/go-goe/goe/utils"
 )
 
 type oneToOne struct {
@@ -54,9 +54,10 @@ func createOneToOne(b body, typeOf reflect.Type) any {
 		b.mapp.db,
 		b.schema,
 		b.mapp.pks[0].tableName,
-		b.fieldName,
+		getColumnName(b.structField, b.driver),
 		b.mapp.tableId,
 		b.fieldId,
+		b.structField.Index,
 		b.driver,
 	)
 	return mto
@@ -64,9 +65,16 @@ func createOneToOne(b body, typeOf reflect.Type) any {
 
 type manyToOne struct {
 	isDefault bool
+	composite []compositeKey // all the columns of a composite foreign key, nil for a single column
 	attributeStrings
 }
 
+// compositeKey is a column of a composite foreign key and the referenced primary key
+type compositeKey struct {
+	attributeName string
+	targetName    string
+}
+
 func (m manyToOne) schema() *string {
 	return m.schemaName
 }
@@ -91,6 +99,24 @@ func (m manyToOne) getAttributeName() string {
 	return m.attributeName
 }
 
+// joinComposite returns the other columns of the composite foreign key to join with target,
+// returns nil if m don't reference target
+func (m manyToOne) joinComposite(target field, build func(column, target model.JoinArgument) model.JoinComposite) []model.JoinComposite {
+	if !slices.Contains(m.composite, compositeKey{attributeName: m.attributeName, targetName: target.getAttributeName()}) {
+		return nil
+	}
+	composite := make([]model.JoinComposite, 0, len(m.composite)-1)
+	for _, k := range m.composite {
+		if k.attributeName == m.attributeName {
+			continue
+		}
+		composite = append(composite, build(
+			model.JoinArgument{Table: m.table(), Name: k.attributeName},
+			model.JoinArgument{Table: target.table(), Name: k.targetName}))
+	}
+	return composite
+}
+
 func createManyToOne(b body, typeOf reflect.Type) any {
 	mto := manyToOne{}
 	targetPks := primaryKeys(typeOf)
@@ -101,17 +127,25 @@ func createManyToOne(b body, typeOf reflect.Type) any {
 		}
 	}
 
-	if count == 0 {
+	fields, targets := compositeForeignKey(b.tables, b.typeOf, b.structField)
+	if count == 0 && fields == nil {
 		return nil
 	}
-	mto.isDefault = getTagValue(b.valueOf.Type().Field(b.fieldId).Tag.Get("goe"), "default:") != ""
+	for i := range fields {
+		mto.composite = append(mto.composite, compositeKey{
+			attributeName: b.driver.KeywordHandler(getColumnName(fields[i], b.driver)),
+			targetName:    b.driver.KeywordHandler(getColumnName(targets[i], b.driver)),
+		})
+	}
+	mto.isDefault = getTagValue(b.structField.Tag.Get("goe"), "default:") != ""
 	mto.attributeStrings = createAttributeStrings(
 		b.mapp.db,
 		b.schema,
 		b.mapp.pks[0].tableName,
-		b.fieldName,
+		getColumnName(b.structField, b.driver),
 		b.mapp.tableId,
 		b.fieldId,
+		b.structField.Index,
 		b.driver,
 	)
 	return mto
@@ -121,19 +155,34 @@ type attributeStrings struct {
 	db            *DB
 	schemaName    *string
 	tableId       int
-	tableName     string
+	tableName     string // name used on the query, the alias if the table is aliased
+	sourceName    string // name of the aliased table, empty if the table is not aliased
 	attributeName string
 	fieldId       int
+	fieldIndex    []int // index sequence of the field on the table struct
+}
+
+func (a attributeStrings) getTable() model.Table {
+	return modelTable(a.schemaName, a.tableName, a.sourceName)
+}
+
+// modelTable returns the table of the query, aliased as table if source is the name of a aliased table
+func modelTable(schema *string, table, source string) model.Table {
+	if source == "" || source == table {
+		return model.Table{Schema: schema, Name: table}
+	}
+	return model.Table{Schema: schema, Name: source, Alias: table}
 }
 
-func createAttributeStrings(db *DB, schema *string, table string, attributeName string, tableId, fieldId int, Driver model.Driver) attributeStrings {
+func createAttributeStrings(db *DB, schema *string, table string, attributeName string, tableId, fieldId int, fieldIndex []int, Driver model.Driver) attributeStrings {
 	return attributeStrings{
 		db:            db,
 		tableName:     table,
 		tableId:       tableId,
 		fieldId:       fieldId,
+		fieldIndex:    fieldIndex,
 		schemaName:    schema,
-		attributeName: Driver.KeywordHandler(utils.ColumnNamePattern(attributeName)),
+		attributeName: Driver.KeywordHandler(attributeName),
 	}
 }
 
@@ -166,10 +215,10 @@ func (p pk) getAttributeName() string {
 	return p.attributeName
 }
 
-func createPk(db *DB, schema *string, table string, attributeName string, autoIncrement bool, tableId, fieldId int, Driver model.Driver) pk {
-	table = Driver.KeywordHandler(utils.TableNamePattern(table))
+func createPk(db *DB, schema *string, table string, attributeName string, autoIncrement bool, tableId, fieldId int, fieldIndex []int, Driver model.Driver) pk {
+	table = Driver.KeywordHandler(table)
 	return pk{
-		attributeStrings: createAttributeStrings(db, schema, table, attributeName, tableId, fieldId, Driver),
+		attributeStrings: createAttributeStrings(db, schema, table, attributeName, tableId, fieldId, fieldIndex, Driver),
 		autoIncrement:    autoIncrement}
 }
 
@@ -202,10 +251,10 @@ func (a att) getAttributeName() string {
 	return a.attributeName
 }
 
-func createAtt(db *DB, attributeName string, schema *string, table string, tableId, fieldId int, isDefault bool, d model.Driver) att {
+func createAtt(db *DB, attributeName string, schema *string, table string, tableId, fieldId int, fieldIndex []int, isDefault bool, d model.Driver) att {
 	return att{
 		isDefault:        isDefault,
-		attributeStrings: createAttributeStrings(db, schema, table, attributeName, tableId, fieldId, d)}
+		attributeStrings: createAttributeStrings(db, schema, table, attributeName, tableId, fieldId, fieldIndex, d)}
 }
 
 func (p pk) buildAttributeSelect(atts []model.Attribute, i int) {
@@ -238,43 +287,43 @@ func (o oneToOne) buildAttributeSelect(atts []model.Attribute, i int) {
 
 func (p pk) buildAttributeInsert(b *builder) {
 	if !p.autoIncrement {
-		b.fieldIds = append(b.fieldIds, p.fieldId)
+		b.fieldIndexes = append(b.fieldIndexes, p.fieldIndex)
 		b.query.Attributes = append(b.query.Attributes, model.Attribute{Name: p.getAttributeName()})
 		return
 	}
 	b.query.ReturningID = &model.Attribute{Name: p.getAttributeName()}
-	b.pkFieldId = p.fieldId
+	b.pkFieldIndex = p.fieldIndex
 }
 
 func (a att) buildAttributeInsert(b *builder) {
-	b.fieldIds = append(b.fieldIds, a.fieldId)
+	b.fieldIndexes = append(b.fieldIndexes, a.fieldIndex)
 	b.query.Attributes = append(b.query.Attributes, model.Attribute{Name: a.getAttributeName()})
 }
 
 func (m manyToOne) buildAttributeInsert(b *builder) {
-	b.fieldIds = append(b.fieldIds, m.fieldId)
+	b.fieldIndexes = append(b.fieldIndexes, m.fieldIndex)
 	b.query.Attributes = append(b.query.Attributes, model.Attribute{Name: m.getAttributeName()})
 }
 
 func (o oneToOne) buildAttributeInsert(b *builder) {
-	b.fieldIds = append(b.fieldIds, o.fieldId)
+	b.fieldIndexes = append(b.fieldIndexes, o.fieldIndex)
 	b.query.Attributes = append(b.query.Attributes, model.Attribute{Name: o.getAttributeName()})
 }
 
-func (p pk) getFieldId() int {
-	return p.fieldId
+func (p pk) getFieldIndex() []int {
+	return p.fieldIndex
 }
 
-func (a att) getFieldId() int {
-	return a.fieldId
+func (a att) getFieldIndex() []int {
+	return a.fieldIndex
 }
 
-func (m manyToOne) getFieldId() int {
-	return m.fieldId
+func (m manyToOne) getFieldIndex() []int {
+	return m.fieldIndex
 }
 
-func (o oneToOne) getFieldId() int {
-	return o.fieldId
+func (o oneToOne) getFieldIndex() []int {
+	return o.fieldIndex
 }
 
 func (p pk) getDefault() bool {
@@ -296,10 +345,12 @@ func (o oneToOne) getDefault() bool {
 type aggregateResult struct {
 	attributeName string
 	tableName     string
+	sourceName    string
 	schemaName    *string
 	aggregateType enum.AggregateType
 	tableId       int
 	db            *DB
+	with          *cte // common table expression of the column, nil if the column is of a table
 }
 
 func (a aggregateResult) buildAttributeSelect(atts []model.Attribute, i int) {
@@ -321,6 +372,10 @@ func (a aggregateResult) getTableId() int {
 	return a.tableId
 }
 
+func (a aggregateResult) getTable() model.Table {
+	return modelTable(a.schemaName, a.tableName, a.sourceName)
+}
+
 func (a aggregateResult) getDb() *DB {
 	return a.db
 }
@@ -328,17 +383,21 @@ func (a aggregateResult) getDb() *DB {
 type functionResult struct {
 	attributeName string
 	tableName     string
+	sourceName    string
 	schemaName    *string
-	functionType  enum.FunctionType
+	function      *model.Function
 	tableId       int
 	db            *DB
+	with          *cte               // common table expression of the column, nil if the column is of a table
+	source        model.FunctionType // function of the select, set if the function has arguments to order or group by the position
 }
 
 func (f functionResult) buildAttributeSelect(atts []model.Attribute, i int) {
 	atts[i] = model.Attribute{
 		Table:        f.tableName,
 		Name:         f.attributeName,
-		FunctionType: f.functionType}
+		FunctionType: functionType(f.function),
+		Function:     f.function}
 }
 
 func (f functionResult) schema() *string {
@@ -353,6 +412,128 @@ func (f functionResult) getTableId() int {
 	return f.tableId
 }
 
+func (f functionResult) getTable() model.Table {
+	return modelTable(f.schemaName, f.tableName, f.sourceName)
+}
+
 func (f functionResult) getDb() *DB {
 	return f.db
 }
+
+type windowResult struct {
+	attributeName string
+	tableName     string
+	sourceName    string
+	schemaName    *string
+	aggregateType enum.AggregateType
+	window        model.Window
+	tableId       int
+	db            *DB
+	with          *cte // common table expression of the column, nil if the column is of a table
+}
+
+func (w windowResult) buildAttributeSelect(atts []model.Attribute, i int) {
+	window := w.window
+	atts[i] = model.Attribute{
+		Table:         w.tableName,
+		Name:          w.attributeName,
+		AggregateType: w.aggregateType,
+		Window:        &window}
+}
+
+func (w windowResult) schema() *string {
+	return w.schemaName
+}
+
+func (w windowResult) table() string {
+	return w.tableName
+}
+
+func (w windowResult) getTableId() int {
+	return w.tableId
+}
+
+func (w windowResult) getTable() model.Table {
+	return modelTable(w.schemaName, w.tableName, w.sourceName)
+}
+
+func (w windowResult) getDb() *DB {
+	return w.db
+}
+
+type expressionResult struct {
+	attributeName string
+	tableName     string
+	sourceName    string
+	schemaName    *string
+	expression    *model.Expression
+	tableId       int
+	db            *DB
+	with          *cte // common table expression of the column, nil if the column is of a table
+	source        any  // selected expression, used to order and group by the position of the expression
+}
+
+func (e expressionResult) buildAttributeSelect(atts []model.Attribute, i int) {
+	atts[i] = model.Attribute{
+		Table:      e.tableName,
+		Name:       e.attributeName,
+		Expression: e.expression}
+}
+
+func (e expressionResult) schema() *string {
+	return e.schemaName
+}
+
+func (e expressionResult) table() string {
+	return e.tableName
+}
+
+func (e expressionResult) getTableId() int {
+	return e.tableId
+}
+
+func (e expressionResult) getTable() model.Table {
+	return modelTable(e.schemaName, e.tableName, e.sourceName)
+}
+
+func (e expressionResult) getDb() *DB {
+	return e.db
+}
+
+type caseResult struct {
+	tableName  string
+	sourceName string
+	schemaName *string
+	c          *model.Case
+	arguments  []any // arguments of the conditions and values on the order of the case
+	tableId    int
+	db         *DB
+	with       *cte // common table expression of the first column, nil if the column is of a table
+	source     any  // selected case, used to order and group by the position of the case
+}
+
+func (c caseResult) buildAttributeSelect(atts []model.Attribute, i int) {
+	atts[i] = model.Attribute{
+		Table: c.tableName,
+		Case:  c.c}
+}
+
+func (c caseResult) schema() *string {
+	return c.schemaName
+}
+
+func (c caseResult) table() string {
+	return c.tableName
+}
+
+func (c caseResult) getTableId() int {
+	return c.tableId
+}
+
+func (c caseResult) getTable() model.Table {
+	return modelTable(c.schemaName, c.tableName, c.sourceName)
+}
+
+func (c caseResult) getDb() *DB {
+	return c.db
+}
@@ -3,6 +3,7 @@ package goe
 import (
 	"maps"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/go-goe/goe/enum"
@@ -10,23 +11,35 @@ import (
 )
 
 type builder struct {
-	query          model.Query
-	modelStart     time.Time
-	pkFieldId      int //insert
-	fields         []field
-	fieldsSelect   []fieldSelect
-	fieldIds       []int           //insert and update
-	joins          []enum.JoinType //select
-	joinsArgs      []field         //select
-	sets           []set
-	whereArguments int
-	tables         map[int]bool
-	filter         *model.Where
+	query           model.Query
+	modelStart      time.Time
+	pkFieldIndex    []int //insert
+	fields          []field
+	fieldsSelect    []fieldSelect
+	fieldIndexes    [][]int         //insert and update
+	joins           []enum.JoinType //select
+	joinsArgs       []field         //select
+	joinsOn         []*joinOn       //select, nil for the joins by a pair of fields
+	sets            []set
+	whereArguments  int
+	tables          map[int]bool
+	filter          *model.Where
+	seek            *model.Where // rows after the cursor, the last where operation
+	havingArguments []any
+	compounds       []model.Compound //select, queries combined by Union, Intersect and Except
+	withs           []*cte           //select, common table expressions used by the where
+}
+
+// joinOn is the condition of a join with a table, the arguments are resolved when the join is created
+type joinOn struct {
+	on        *model.Where
+	arguments []any
 }
 
 type set struct {
-	attribute field
-	value     any
+	attribute  field
+	value      any
+	expression *model.Expression // set instead of the value if not nil
 }
 
 func createBuilder(typeQuery enum.QueryType) builder {
@@ -50,31 +63,101 @@ func (b *builder) buildSelectJoins(join enum.JoinType, fields []field) {
 	b.joins = append(b.joins, join)
 	b.joinsArgs[j] = fields[0]
 	b.joinsArgs[j+1] = fields[1]
+	b.joinsOn = append(b.joinsOn, nil)
+}
+
+// buildSelectJoinsOn joins the table of table with the condition on, a cross join if on is nil
+func (b *builder) buildSelectJoinsOn(join enum.JoinType, table field, on *joinOn) {
+	b.joinsArgs = append(b.joinsArgs, table, table)
+	b.joins = append(b.joins, join)
+	b.joinsOn = append(b.joinsOn, on)
+}
+
+// isTableJoin reports whether the join i is made by JoinOn or CrossJoin, instead of a pair of fields
+func (b *builder) isTableJoin(i int) bool {
+	return b.joins[i] == enum.CrossJoin || (i < len(b.joinsOn) && b.joinsOn[i] != nil)
 }
 
 func (b *builder) buildSqlSelect() {
 	b.buildTables()
 	b.buildWhere()
+	if len(b.havingArguments) != 0 {
+		b.query.Arguments = append(slices.Clip(b.query.Arguments), b.havingArguments...)
+	}
+	if b.query.Having != nil {
+		indexSubqueries(b.query.Having, len(b.query.Arguments)-len(b.havingArguments)+1)
+		correlateSubqueries(b.query.Having, queryTables(b.query, nil))
+	}
+	b.buildCompounds()
+	b.buildAttributesArguments()
+	b.buildWiths()
 	b.query.Header.ModelBuild = time.Since(b.modelStart)
 }
 
-func (b *builder) buildSqlInsert(v reflect.Value) (pkFieldId int) {
+// buildCompounds appends the combined queries and the arguments after the arguments of the query,
+// the common table expressions of the combined queries are moved to the query
+func (b *builder) buildCompounds() {
+	b.query.With = nil
+	if len(b.compounds) == 0 {
+		return
+	}
+	b.query.Compound = make([]model.Compound, len(b.compounds))
+	for i, c := range b.compounds {
+		b.query.With = appendWiths(b.query.With, c.Query.With, "")
+		c.Query.Arguments = c.Query.Arguments[withArguments(c.Query.With):]
+		c.Query.With = nil
+		reindexQuery(&c.Query, len(b.query.Arguments)+1)
+		b.query.Arguments = append(slices.Clip(b.query.Arguments), c.Query.Arguments...)
+		b.query.Compound[i] = c
+	}
+}
+
+// reindexQuery sets the positions of the arguments of a built query starting at index,
+// on the order of the arguments: withs, attributes, joins, where, having and compounds. Returns the position after the arguments
+func reindexQuery(q *model.Query, index int) int {
+	q.With = slices.Clone(q.With)
+	for i := range q.With {
+		index = reindexQuery(&q.With[i].Query, index)
+	}
+	index = indexAttributes(q, index)
+	q.Joins = slices.Clone(q.Joins)
+	for i := range q.Joins {
+		if q.Joins[i].On != nil {
+			q.Joins[i].OnIndex = index
+			index = indexSubqueries(q.Joins[i].On, index)
+		}
+	}
+	if q.Where != nil {
+		q.WhereIndex = index
+		index = indexSubqueries(q.Where, index)
+	}
+	if q.Having != nil {
+		index = indexSubqueries(q.Having, index)
+	}
+	q.Compound = slices.Clone(q.Compound)
+	for i := range q.Compound {
+		index = reindexQuery(&q.Compound[i].Query, index)
+	}
+	return index
+}
+
+func (b *builder) buildSqlInsert(v reflect.Value) (pkFieldIndex []int) {
 	b.buildInsert()
-	pkFieldId = b.buildValues(v)
+	pkFieldIndex = b.buildValues(v)
 	b.query.Header.ModelBuild = time.Since(b.modelStart)
-	return pkFieldId
+	return pkFieldIndex
 }
 
-func (b *builder) buildSqlInsertBatch(v reflect.Value) (pkFieldId int) {
+func (b *builder) buildSqlInsertBatch(v reflect.Value) (pkFieldIndex []int) {
 	b.buildInsert()
-	pkFieldId = b.buildBatchValues(v)
+	pkFieldIndex = b.buildBatchValues(v)
 	b.query.Header.ModelBuild = time.Since(b.modelStart)
-	return pkFieldId
+	return pkFieldIndex
 }
 
 func (b *builder) buildSqlDelete() {
 	b.query.Tables = make([]model.Table, 1)
-	b.query.Tables[0] = model.Table{Schema: b.fields[0].schema(), Name: b.fields[0].table()}
+	b.query.Tables[0] = b.fields[0].getTable()
 	b.buildWhere()
 	b.query.Header.ModelBuild = time.Since(b.modelStart)
 }
@@ -91,90 +174,221 @@ func (b *builder) buildWhere() {
 		b.query.Where = b.filter
 	}
 
+	if b.seek != nil && b.query.Where != nil {
+		b.query.Where = &model.Where{
+			Operator:        enum.And,
+			Type:            enum.LogicalWhere,
+			FirstOperation:  b.query.Where,
+			SecondOperation: b.seek,
+		}
+	} else if b.seek != nil {
+		b.query.Where = b.seek
+	}
+
 	if b.query.Where == nil {
 		return
 	}
 	b.query.WhereIndex = len(b.query.Arguments) - b.whereArguments + 1
+	indexSubqueries(b.query.Where, b.query.WhereIndex)
+	correlateSubqueries(b.query.Where, queryTables(b.query, nil))
+}
+
+// indexSubqueries sets the WhereIndex of the subqueries to the position of the
+// first argument of the subquery on the outer query, index is the position of
+// the first argument of w. Returns the position after the arguments of w
+func indexSubqueries(w *model.Where, index int) int {
+	switch w.Type {
+	case enum.LogicalWhere:
+		index = indexSubqueries(w.FirstOperation, index)
+		return indexSubqueries(w.SecondOperation, index)
+	case enum.OperationWhere:
+		return index + len(attributeArguments(nil, w.Attribute)) + 1
+	case enum.OperationBetweenWhere:
+		return index + len(attributeArguments(nil, w.Attribute)) + 2
+	case enum.OperationInWhere, enum.OperationExistsWhere:
+		index += len(attributeArguments(nil, w.Attribute))
+		if !w.MergedIn {
+			return index + int(w.SizeIn)
+		}
+		reindexQuery(w.QueryIn, index)
+		return index + len(w.QueryIn.Arguments)
+	}
+	return index
+}
+
+// correlateSubqueries removes from the subqueries the tables of the outer queries used by the where,
+// so a where.EqualsArg with a outer table references the row of the outer query.
+// The tables selected or joined by the subquery are never removed
+func correlateSubqueries(w *model.Where, outer map[string]bool) {
+	switch w.Type {
+	case enum.LogicalWhere:
+		correlateSubqueries(w.FirstOperation, outer)
+		correlateSubqueries(w.SecondOperation, outer)
+	case enum.OperationInWhere, enum.OperationExistsWhere:
+		if !w.MergedIn {
+			return
+		}
+		own := make(map[string]bool)
+		for _, a := range w.QueryIn.Attributes {
+			own[a.Table] = true
+		}
+		for _, j := range w.QueryIn.Joins {
+			own[j.Table.Reference()] = true
+		}
+
+		tables := make([]model.Table, 0, len(w.QueryIn.Tables))
+		for _, t := range w.QueryIn.Tables {
+			if own[t.Reference()] || !outer[t.Reference()] {
+				tables = append(tables, t)
+			}
+		}
+		w.QueryIn.Tables = tables
+
+		if w.QueryIn.Where != nil {
+			correlateSubqueries(w.QueryIn.Where, queryTables(*w.QueryIn, outer))
+		}
+	}
+}
+
+// queryTables returns the tables of the query and the joins with the outer tables
+func queryTables(query model.Query, outer map[string]bool) map[string]bool {
+	tables := maps.Clone(outer)
+	if tables == nil {
+		tables = make(map[string]bool)
+	}
+	for _, t := range query.Tables {
+		tables[t.Reference()] = true
+	}
+	for _, j := range query.Joins {
+		tables[j.Table.Reference()] = true
+	}
+	return tables
 }
 
 func (b *builder) buildTables() {
 	if len(b.joins) != 0 {
 		b.query.Joins = make([]model.Join, len(b.joins))
 		tables := maps.Clone(b.tables)
-		if !tables[b.joinsArgs[0].getTableId()] {
-			b.query.Tables = append(b.query.Tables, model.Table{Schema: b.joinsArgs[0].schema(), Name: b.joinsArgs[0].table()})
-			tables[b.joinsArgs[0].getTableId()] = true
+		var from fieldSelect = b.joinsArgs[0]
+		if b.isTableJoin(0) {
+			from = b.fieldsSelect[0]
+		}
+		if !tables[from.getTableId()] {
+			b.query.Tables = append(b.query.Tables, from.getTable())
+			tables[from.getTableId()] = true
 		}
 		c := 1
 		for i := range b.joins {
-			buildJoins(i, b.query.Joins, b.joins[i], b.joinsArgs[i+c-1], b.joinsArgs[i+c-1+1], tables)
+			if b.isTableJoin(i) {
+				b.query.Joins[i] = model.Join{Table: b.joinsArgs[i+c-1].getTable(), JoinOperation: b.joins[i]}
+				tables[b.joinsArgs[i+c-1].getTableId()] = true
+			} else {
+				buildJoins(i, b.query.Joins, b.joins[i], b.joinsArgs[i+c-1], b.joinsArgs[i+c-1+1], tables)
+			}
 			c++
 		}
+		b.buildJoinsOn()
 		return
 	}
 	if len(b.query.Tables) == 0 {
-		b.query.Tables = append(b.query.Tables, model.Table{Schema: b.fieldsSelect[0].schema(), Name: b.fieldsSelect[0].table()})
+		b.query.Tables = append(b.query.Tables, b.fieldsSelect[0].getTable())
+	}
+}
+
+// buildJoinsOn sets the conditions of the joins, the arguments of the conditions are before the where arguments
+func (b *builder) buildJoinsOn() {
+	var arguments []any
+	for i, j := range b.joinsOn {
+		if j == nil {
+			continue
+		}
+		b.query.Joins[i].On = j.on
+		b.query.Joins[i].OnIndex = len(arguments) + 1
+		indexSubqueries(j.on, b.query.Joins[i].OnIndex)
+		correlateSubqueries(j.on, queryTables(b.query, nil))
+		arguments = append(arguments, j.arguments...)
+	}
+	if len(arguments) != 0 {
+		b.query.Arguments = append(arguments, b.query.Arguments...)
 	}
 }
 
 func buildJoins(i int, joins []model.Join, join enum.JoinType, f1, f2 field, tables map[int]bool) {
 	if !tables[f1.getTableId()] {
 		joins[i] = model.Join{
-			Table:          model.Table{Schema: f1.schema(), Name: f1.table()},
+			Table:          f1.getTable(),
 			FirstArgument:  model.JoinArgument{Table: f1.table(), Name: f1.getAttributeName()},
 			JoinOperation:  join,
-			SecondArgument: model.JoinArgument{Table: f2.table(), Name: f2.getAttributeName()}}
+			SecondArgument: model.JoinArgument{Table: f2.table(), Name: f2.getAttributeName()},
+			Composite:      joinComposite(f1, f2)}
 
 		tables[f1.getTableId()] = true
 		return
 	}
 	joins[i] = model.Join{
-		Table:          model.Table{Schema: f2.schema(), Name: f2.table()},
+		Table:          f2.getTable(),
 		FirstArgument:  model.JoinArgument{Table: f2.table(), Name: f2.getAttributeName()},
 		JoinOperation:  join,
-		SecondArgument: model.JoinArgument{Table: f1.table(), Name: f1.getAttributeName()}}
+		SecondArgument: model.JoinArgument{Table: f1.table(), Name: f1.getAttributeName()},
+		Composite:      joinComposite(f2, f1)}
 
 	tables[f2.getTableId()] = true
 }
 
+// joinComposite returns the other columns to join when first or second
+// is a column of a composite foreign key referencing the other argument
+func joinComposite(first, second field) []model.JoinComposite {
+	if m, ok := first.(manyToOne); ok && len(m.composite) != 0 {
+		return m.joinComposite(second, func(column, target model.JoinArgument) model.JoinComposite {
+			return model.JoinComposite{FirstArgument: column, SecondArgument: target}
+		})
+	}
+	if m, ok := second.(manyToOne); ok && len(m.composite) != 0 {
+		return m.joinComposite(first, func(column, target model.JoinArgument) model.JoinComposite {
+			return model.JoinComposite{FirstArgument: target, SecondArgument: column}
+		})
+	}
+	return nil
+}
+
 func (b *builder) buildInsert() {
-	b.fieldIds = make([]int, 0, len(b.fields))
+	b.fieldIndexes = make([][]int, 0, len(b.fields))
 	b.query.Attributes = make([]model.Attribute, 0, len(b.fields))
 
 	b.query.Tables = make([]model.Table, 1)
-	b.query.Tables[0] = model.Table{Schema: b.fields[0].schema(), Name: b.fields[0].table()}
+	b.query.Tables[0] = b.fields[0].getTable()
 	for i := range b.fields {
 		b.fields[i].buildAttributeInsert(b)
 	}
 }
 
-func (b *builder) buildValues(value reflect.Value) int {
-	b.query.Arguments = make([]any, len(b.fieldIds))
+func (b *builder) buildValues(value reflect.Value) []int {
+	b.query.Arguments = make([]any, len(b.fieldIndexes))
 
-	for c, i := range b.fieldIds {
-		b.query.Arguments[c] = value.Field(i).Interface()
+	for c, i := range b.fieldIndexes {
+		b.query.Arguments[c] = value.FieldByIndex(i).Interface()
 	}
-	b.query.SizeArguments = len(b.fieldIds)
-	return b.pkFieldId
+	b.query.SizeArguments = len(b.fieldIndexes)
+	return b.pkFieldIndex
 
 }
 
-func (b *builder) buildBatchValues(value reflect.Value) int {
-	b.query.Arguments = make([]any, len(b.fieldIds)*value.Len())
+func (b *builder) buildBatchValues(value reflect.Value) []int {
+	b.query.Arguments = make([]any, len(b.fieldIndexes)*value.Len())
 
 	c := 0
 	for j := 0; j < value.Len(); j++ {
 		c = buildBatchValues(value.Index(j), b, c)
 	}
 	b.query.BatchSizeQuery = value.Len()
-	b.query.SizeArguments = len(b.fieldIds)
-	return b.pkFieldId
+	b.query.SizeArguments = len(b.fieldIndexes)
+	return b.pkFieldIndex
 
 }
 
 func buildBatchValues(value reflect.Value, b *builder, c int) int {
-	for _, i := range b.fieldIds {
-		b.query.Arguments[c] = value.Field(i).Interface()
+	for _, i := range b.fieldIndexes {
+		b.query.Arguments[c] = value.FieldByIndex(i).Interface()
 		c++
 	}
 	return c
@@ -188,11 +402,10 @@ func (b *builder) buildUpdate() {
 func (b *builder) buildSets() {
 	b.query.Attributes = make([]model.Attribute, len(b.sets))
 	b.query.Tables = make([]model.Table, 1)
-	b.query.Tables[0] = model.Table{Schema: b.sets[0].attribute.schema(), Name: b.sets[0].attribute.table()}
-	b.query.Arguments = make([]any, len(b.sets))
+	b.query.Tables[0] = b.sets[0].attribute.getTable()
+	b.query.Arguments = b.setsArguments()
 
 	for i := range b.sets {
-		b.query.Attributes[i] = model.Attribute{Name: b.sets[i].attribute.getAttributeName()}
-		b.query.Arguments[i] = b.sets[i].value
+		b.query.Attributes[i] = model.Attribute{Name: b.sets[i].attribute.getAttributeName(), Expression: b.sets[i].expression}
 	}
 }
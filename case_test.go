@@ -0,0 +1,48 @@
+package goe
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/go-goe/goe/query/aggregate"
+	"github.com/go-goe/goe/query/expr"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestSelectCase(t *testing.T) {
+	db, d := openTest(t, false)
+
+	size := expr.Case().When(where.Greater(&db.Animal.Age, 10), "old").When(where.Equals(&db.Animal.Age, 0), &db.Animal.Name).Else("young")
+	_, err := Select[struct {
+		Size    string
+		Animals int64
+	}](size, aggregate.Count(&db.Animal.Id)).Where(where.Equals(&db.Animal.HabitatId, 3)).GroupBy(size).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected case, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	c := q.Attributes[0].Case
+	if c == nil || len(c.Whens) != 2 || c.Else == nil || c.Else.Value != "young" {
+		t.Fatalf("Expected case with two whens and else, got %+v", q.Attributes[0])
+	}
+	if c.Whens[0].Then.Value != "old" || c.Whens[1].Then.Attribute == nil || c.Whens[1].Then.Attribute.Name != `"name"` {
+		t.Errorf("Expected the then values, got %+v", c.Whens)
+	}
+	if !slices.Contains(q.Arguments, any(10)) || !slices.Contains(q.Arguments, any("young")) || q.Arguments[len(q.Arguments)-1] != 3 {
+		t.Errorf("Expected the case arguments before the where argument, got %v", q.Arguments)
+	}
+	if len(q.GroupBy) != 1 {
+		t.Errorf("Expected group by case, got %+v", q.GroupBy)
+	}
+}
+
+func TestSelectCaseBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	_, err := Select[struct{ Size string }](expr.Case().When(where.Greater(&db.Animal.Age, 10), "old").Else("young")).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestUnion(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Union(
+		Select[struct{ Name string }](&db.Animal.Name).Where(where.Equals(&db.Animal.Name, "cat")),
+		Select[struct{ Name string }](&db.Habitat.Name).Where(where.Equals(&db.Habitat.Name, "forest")).OrderByAsc(&db.Habitat.Name),
+	).OrderByAsc(&db.Animal.Name).Take(5).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected union, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	if len(q.Compound) != 1 || q.Compound[0].Type != enum.UnionCompound {
+		t.Fatalf("Expected a union, got %+v", q.Compound)
+	}
+	if second := q.Compound[0].Query; second.OrderBy != nil || len(second.Arguments) != 1 || second.Arguments[0] != "forest" {
+		t.Errorf("Expected the second query without order and with its argument, got %+v", second)
+	}
+	if len(q.OrderBy) != 1 || q.Limit != 5 {
+		t.Errorf("Expected the order and the limit of the combined rows, got %+v %v", q.OrderBy, q.Limit)
+	}
+	if len(q.Arguments) != 2 || q.Arguments[0] != "cat" || q.Arguments[1] != "forest" {
+		t.Errorf("Expected the arguments of both queries, got %v", q.Arguments)
+	}
+}
+
+func TestUnionBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	_, err := Except(
+		Select[struct{ Name string }](&db.Animal.Name),
+		Select[struct{ Name string }](&db.Habitat.Name),
+	).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package goe
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+)
+
+// cte is a common table expression created by With, the query is used by all the queries that select the cte
+type cte struct {
+	db        *DB
+	name      string
+	columns   []string
+	recursive bool
+	query     model.Query
+}
+
+// cteColumn is a column of a common table expression, used as a field of a table
+type cteColumn struct {
+	cte *cte
+	attributeStrings
+}
+
+func (c cteColumn) schema() *string {
+	return nil
+}
+
+func (c cteColumn) getDb() *DB {
+	return c.db
+}
+
+func (c cteColumn) isPrimaryKey() bool {
+	return false
+}
+
+func (c cteColumn) getTableId() int {
+	return c.tableId
+}
+
+func (c cteColumn) table() string {
+	return c.tableName
+}
+
+func (c cteColumn) getAttributeName() string {
+	return c.attributeName
+}
+
+func (c cteColumn) getFieldIndex() []int {
+	return c.fieldIndex
+}
+
+func (c cteColumn) getDefault() bool {
+	return false
+}
+
+func (c cteColumn) buildAttributeSelect(atts []model.Attribute, i int) {
+	atts[i] = model.Attribute{
+		Table: c.tableName,
+		Name:  c.attributeName,
+	}
+}
+
+func (c cteColumn) buildAttributeInsert(b *builder) {
+	panic("goe: invalid insert. a common table expression can only be selected")
+}
+
+// With returns a common table expression named name with the rows of query, the fields of the
+// returned struct are used as the fields of a table on select, join and where by the queries made
+// with the returned context. The columns of the expression are named by the fields of T, as the columns of a table.
+//
+// # Example
+//
+//	type habitatCount struct {
+//		HabitatId uuid.UUID
+//		Animals   int64
+//	}
+//	ctx, counts := goe.With(context.Background(), "habitat_counts", goe.Select[habitatCount](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+//		GroupBy(&db.Animal.HabitatId))
+//
+//	// WITH habitat_counts (habitat_id, animals) AS (SELECT ...)
+//	// SELECT habitats.name, habitat_counts.animals FROM habitats JOIN habitat_counts ON (...)
+//	rows, err := goe.SelectContext[struct {
+//		Name    string
+//		Animals int64
+//	}](ctx, &db.Habitat.Name, &counts.Animals).Join(&db.Habitat.Id, &counts.HabitatId).AsSlice()
+func With[T any](ctx context.Context, name string, query stateSelect[T]) (context.Context, *T) {
+	c, table, fields := newCte[T](ctx, name, query.builder.fieldsSelect)
+	c.query = query.AsQuery()
+	return withScope(ctx, table, aliasKey{}, fields), table
+}
+
+// WithRecursive returns a recursive common table expression, the rows of anchor combined by
+// union all with the rows of recursive. The function recursive receives the expression to
+// select or join the rows of the previous step and the context to make the recursive query,
+// as [With] the returned struct is used as a table by the queries made with the returned context.
+//
+// # Example
+//
+//	// all the subordinates of the employee 1
+//	ctx, tree := goe.WithRecursive(context.Background(), "tree",
+//		goe.Select[Employee](&db.Employee.Id, &db.Employee.Name, &db.Employee.ManagerId).
+//			Where(where.Equals(&db.Employee.Id, 1)),
+//		func(ctx context.Context, tree *Employee) model.Query {
+//			return goe.SelectContext[Employee](ctx, &db.Employee.Id, &db.Employee.Name, &db.Employee.ManagerId).
+//				Join(&db.Employee.ManagerId, &tree.Id).AsQuery()
+//		})
+//
+//	employees, err := goe.ListContext(ctx, tree).AsSlice()
+func WithRecursive[T any](ctx context.Context, name string, anchor stateSelect[T], recursive func(ctx context.Context, cte *T) model.Query) (context.Context, *T) {
+	c, table, fields := newCte[T](ctx, name, anchor.builder.fieldsSelect)
+	c.recursive = true
+	ctx = withScope(ctx, table, aliasKey{}, fields)
+
+	query := recursive(ctx, table)
+	if len(query.Attributes) != len(anchor.builder.fieldsSelect) {
+		panic("goe: invalid with. try selecting the same number of columns on anchor and recursive")
+	}
+	query.OrderBy, query.Limit, query.Offset = nil, 0, 0
+	anchor.builder.compounds = append(slices.Clip(anchor.builder.compounds), model.Compound{Type: enum.UnionAllCompound, Query: query})
+	c.query = anchor.AsQuery()
+	return ctx, table
+}
+
+// newCte maps the fields of a new T as the columns of a common table expression,
+// the fields are added to the scope of the context returned by With
+func newCte[T any](ctx context.Context, name string, fieldsSelect []fieldSelect) (*cte, *T, map[uintptr]field) {
+	if name == "" {
+		panic("goe: invalid with. try sending a non-empty name")
+	}
+	db := fieldsSelect[0].getDb()
+	table := new(T)
+	valueOf := reflect.ValueOf(table).Elem()
+	if valueOf.Kind() != reflect.Struct {
+		panic("goe: invalid with. try using a struct as the result of the query")
+	}
+
+	tableId := scopeOf(ctx).nextId()
+	c := &cte{db: db, name: db.driver.KeywordHandler(name)}
+	fields := make(map[uintptr]field)
+	for structField, fieldOf := range indexedFieldsOf(valueOf) {
+		if isIgnored(structField) {
+			continue
+		}
+		column := db.driver.KeywordHandler(getColumnName(structField, db.driver))
+		c.columns = append(c.columns, column)
+		fields[uintptr(fieldOf.Addr().UnsafePointer())] = cteColumn{
+			cte: c,
+			attributeStrings: attributeStrings{
+				db:            db,
+				tableId:       tableId,
+				tableName:     c.name,
+				attributeName: column,
+				fieldIndex:    structField.Index,
+			},
+		}
+	}
+	if len(c.columns) != len(fieldsSelect) {
+		panic("goe: invalid with. try using a struct with one field for each selected column")
+	}
+	return c, table, fields
+}
+
+// addWith adds the common table expression of f to the query, if f is a column of a expression
+// or a aggregate, function, window or arithmetic of a column of a expression
+func (b *builder) addWith(f fieldSelect) {
+	if c := withOf(f); c != nil && !slices.Contains(b.withs, c) {
+		b.withs = append(b.withs, c)
+	}
+}
+
+// withOf returns the common table expression used by f, nil if f don't use a expression
+func withOf(f fieldSelect) *cte {
+	switch f := f.(type) {
+	case cteColumn:
+		return f.cte
+	case aggregateResult:
+		return f.with
+	case functionResult:
+		return f.with
+	case windowResult:
+		return f.with
+	case expressionResult:
+		return f.with
+	case caseResult:
+		return f.with
+	}
+	return nil
+}
+
+// buildWiths sets the common table expressions used by the query, the expressions used by a
+// expression are added before it. The arguments of the expressions are before all the arguments of the query
+func (b *builder) buildWiths() {
+	for _, f := range b.fieldsSelect {
+		b.addWith(f)
+	}
+	for _, f := range b.joinsArgs {
+		b.addWith(f)
+	}
+	// the expressions of the combined queries are moved to the query by buildCompounds
+	compounds := b.query.With
+	withs := make([]model.With, 0, len(b.withs)+len(compounds))
+	for _, c := range b.withs {
+		withs = appendWiths(withs, c.query.With, c.name)
+		query := c.query
+		query.Arguments = query.Arguments[withArguments(query.With):]
+		query.With = nil
+		withs = appendWiths(withs, []model.With{{Name: c.name, Columns: c.columns, Recursive: c.recursive, Query: query}}, "")
+	}
+	withs = appendWiths(withs, compounds, "")
+	if len(withs) == 0 {
+		return
+	}
+
+	arguments := make([]any, 0, withArguments(withs))
+	for _, w := range withs {
+		arguments = append(arguments, w.Query.Arguments...)
+	}
+	b.query.With = withs
+	b.query.Arguments = append(arguments, b.query.Arguments...)
+	reindexQuery(&b.query, 1)
+}
+
+// withArguments returns the number of arguments of the expressions
+func withArguments(withs []model.With) int {
+	n := 0
+	for _, w := range withs {
+		n += len(w.Query.Arguments)
+	}
+	return n
+}
+
+// appendWiths appends the expressions not added to withs, except the expression named skip
+func appendWiths(withs []model.With, add []model.With, skip string) []model.With {
+	for _, w := range add {
+		if w.Name != skip && !slices.ContainsFunc(withs, func(with model.With) bool { return with.Name == w.Name }) {
+			withs = append(withs, w)
+		}
+	}
+	return withs
+}
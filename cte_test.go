@@ -0,0 +1,60 @@
+package goe
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/query/aggregate"
+	"github.com/go-goe/goe/query/where"
+)
+
+type habitatCount struct {
+	HabitatId int
+	Animals   int64
+}
+
+func TestWith(t *testing.T) {
+	db, d := openTest(t, false)
+
+	ctx, counts := With(context.Background(), "habitat_counts", Select[habitatCount](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+		Where(where.Equals(&db.Animal.Name, "cat")).GroupBy(&db.Animal.HabitatId))
+	_, err := SelectContext[struct {
+		Name    string
+		Animals int64
+	}](ctx, &db.Habitat.Name, &counts.Animals).Join(&db.Habitat.Id, &counts.HabitatId).
+		Where(where.Equals(&db.Habitat.Name, "forest")).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected with, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	if len(q.With) != 1 || q.With[0].Name != `"habitat_counts"` || len(q.With[0].Columns) != 2 {
+		t.Fatalf("Expected the expression habitat_counts with two columns, got %+v", q.With)
+	}
+	if len(q.Arguments) != 2 || q.Arguments[0] != "cat" || q.Arguments[1] != "forest" {
+		t.Errorf("Expected the arguments of the expression before the query arguments, got %v", q.Arguments)
+	}
+	if q.WhereIndex != 2 {
+		t.Errorf("Expected the where arguments starting at 2, got %v", q.WhereIndex)
+	}
+
+	// the expression is a table only for the queries made with the returned context
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for the expression out of the context")
+		}
+	}()
+	Select[struct{ Animals int64 }](&counts.Animals).AsSlice()
+}
+
+func TestWithBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	ctx, counts := With(context.Background(), "habitat_counts", Select[habitatCount](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+		GroupBy(&db.Animal.HabitatId))
+	_, err := ListContext(ctx, counts).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
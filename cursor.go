@@ -0,0 +1,403 @@
+package goe
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/internal/operand"
+	"github.com/go-goe/goe/model"
+)
+
+// Cursor is a opaque token returned by [stateSelect.AsCursor] to get the next or the previous page,
+// the token is signed by the database and a changed token, or a token of a query with other
+// result type, order, where or arguments, returns [ErrInvalidCursor].
+//
+// The empty Cursor gets the first page.
+type Cursor string
+
+type CursorPage[T any] struct {
+	PageValues int `json:"pageValues"`
+	PageSize   int `json:"pageSize"`
+
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	Previous        Cursor `json:"previous,omitempty"`
+	HasNextPage     bool   `json:"hasNextPage"`
+	Next            Cursor `json:"next,omitempty"`
+
+	Values []T `json:"values"`
+}
+
+// cursorToken is the payload of a Cursor, the keys are the values of the seek columns
+// on the last row of the page, or on the first row if the cursor is backward
+type cursorToken struct {
+	Backward bool              `json:"b,omitempty"`
+	Keys     []json.RawMessage `json:"k"`
+}
+
+// seekColumn is a column of the order used to seek the rows after the cursor
+type seekColumn struct {
+	field  field
+	column int // position on the selected columns
+	desc   bool
+}
+
+// columnPath is the field of the result struct scanned by a selected column
+type columnPath struct {
+	field  []int // field on the result struct
+	nested []int // field on the nested table struct, nil if the column is scanned on field
+}
+
+// AsCursor return a page of size rows after the cursor as [CursorPage], using a seek on the order
+// instead of a offset, the order is tie-breaking by the selected primary keys of the first table.
+//
+// The order columns needs to be selected and not null, the empty cursor gets the first page.
+//
+// Default value for size is 10.
+//
+// # Example
+//
+//	var p *goe.CursorPage[Animal]
+//	p, err = goe.List(db.Animal).OrderByDesc(&db.Animal.CreateAt).AsCursor("", 20)
+//
+//	// where (animals.create_at < $1) OR (animals.create_at = $1 AND animals.id > $2)
+//	p, err = goe.List(db.Animal).OrderByDesc(&db.Animal.CreateAt).AsCursor(p.Next, 20)
+func (s stateSelect[T]) AsCursor(after Cursor, size int) (*CursorPage[T], error) {
+	if size <= 0 {
+		size = 10
+	}
+
+	db := s.builder.fieldsSelect[0].getDb()
+	columns := seekColumns(s.builder.fieldsSelect, s.builder.query.OrderBy)
+	paths := columnPaths(db.tables, reflect.TypeFor[T](), len(s.builder.fieldsSelect), s.nested)
+	signature, err := cursorSignature(reflect.TypeFor[T](), columns, &s.builder)
+	if err != nil {
+		return nil, err
+	}
+
+	var token cursorToken
+	if after != "" {
+		if token, err = decodeCursor(db.cursorKey, signature, after); err != nil {
+			return nil, err
+		}
+		values, err := cursorValues(reflect.TypeFor[T](), paths, columns, token.Keys)
+		if err != nil {
+			return nil, err
+		}
+		s.builder.seek = seekWhere(&s.builder, columns, values, token.Backward)
+	}
+
+	s.builder.query.OrderBy = make([]model.OrderBy, len(columns))
+	for i, c := range columns {
+		s.builder.query.OrderBy[i] = model.OrderBy{
+			Attribute: model.Attribute{Table: c.field.table(), Name: c.field.getAttributeName()},
+			Desc:      c.desc != token.Backward,
+		}
+	}
+	s.builder.query.Offset = 0
+	s.builder.query.Limit = size + 1
+
+	rows, err := s.AsSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(rows) > size
+	if more {
+		rows = rows[:size]
+	}
+
+	p := &CursorPage[T]{PageSize: size, PageValues: len(rows), Values: rows}
+	if token.Backward {
+		slices.Reverse(rows)
+		p.HasPreviousPage, p.HasNextPage = more, true
+	} else {
+		p.HasPreviousPage, p.HasNextPage = after != "", more
+	}
+
+	if len(rows) == 0 {
+		p.HasPreviousPage, p.HasNextPage = false, false
+		return p, nil
+	}
+	if p.HasNextPage {
+		if p.Next, err = encodeCursor(db.cursorKey, signature, false, reflect.ValueOf(rows[len(rows)-1]), paths, columns); err != nil {
+			return nil, err
+		}
+	}
+	if p.HasPreviousPage {
+		if p.Previous, err = encodeCursor(db.cursorKey, signature, true, reflect.ValueOf(rows[0]), paths, columns); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// newCursorKey returns the key used to sign the cursors, a random key if secret is empty.
+// The cursors signed by a random key are invalid after a restart and on other instances
+func newCursorKey(secret []byte) []byte {
+	if len(secret) != 0 {
+		return slices.Clone(secret)
+	}
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// seekColumns returns the order columns followed by the selected primary keys of the first table
+func seekColumns(fieldsSelect []fieldSelect, orderBy []model.OrderBy) []seekColumn {
+	columns := make([]seekColumn, 0, len(orderBy)+1)
+	for _, o := range orderBy {
+		c := slices.IndexFunc(fieldsSelect, func(fs fieldSelect) bool {
+			f, ok := fs.(field)
+			return ok && o.Attribute.Function == nil && o.Attribute.Expression == nil && o.Attribute.AggregateType == 0 &&
+				f.table() == o.Attribute.Table && f.getAttributeName() == o.Attribute.Name
+		})
+		if c == -1 {
+			panic("goe: invalid cursor. try ordering by columns selected by the query")
+		}
+		columns = append(columns, seekColumn{field: fieldsSelect[c].(field), column: c, desc: o.Desc})
+	}
+
+	unique := false
+	for c, fs := range fieldsSelect {
+		f, ok := fs.(field)
+		if !ok || !f.isPrimaryKey() || f.getTableId() != fieldsSelect[0].getTableId() {
+			continue
+		}
+		unique = true
+		if !slices.ContainsFunc(columns, func(s seekColumn) bool { return s.column == c }) {
+			columns = append(columns, seekColumn{field: f, column: c})
+		}
+	}
+	if !unique {
+		panic("goe: invalid cursor. try selecting the primary key of the first table")
+	}
+	return columns
+}
+
+// seekWhere returns the where of the rows after values on the order of columns,
+// or before values if backward. For (a asc, b desc) returns (a > $1) OR (a = $1 AND b < $2)
+func seekWhere(b *builder, columns []seekColumn, values []any, backward bool) *model.Where {
+	var seek *model.Where
+	for i := range columns {
+		operator := enum.Greater
+		if columns[i].desc != backward {
+			operator = enum.Less
+		}
+		term := seekOperation(columns[i].field, operator, values[i])
+		for j := i - 1; j >= 0; j-- {
+			term = &model.Where{
+				Operator:        enum.And,
+				Type:            enum.LogicalWhere,
+				FirstOperation:  seekOperation(columns[j].field, enum.Equals, values[j]),
+				SecondOperation: term,
+			}
+		}
+		if seek == nil {
+			seek = term
+			continue
+		}
+		seek = &model.Where{
+			Operator:        enum.Or,
+			Type:            enum.LogicalWhere,
+			FirstOperation:  seek,
+			SecondOperation: term,
+		}
+	}
+
+	b.query.Arguments = slices.Clip(b.query.Arguments)
+	appendSeekArguments(b, seek)
+	return seek
+}
+
+func seekOperation(f field, operator enum.OperatorType, value any) *model.Where {
+	return &model.Where{
+		Type:      enum.OperationWhere,
+		Operator:  operator,
+		Value:     operand.Value{Value: value},
+		Table:     f.getTable(),
+		TableId:   f.getTableId(),
+		Attribute: model.Attribute{Table: f.table(), Name: f.getAttributeName()},
+	}
+}
+
+// appendSeekArguments appends the values of the seek on the order the where is visited
+func appendSeekArguments(b *builder, w *model.Where) {
+	if w.Type == enum.LogicalWhere {
+		appendSeekArguments(b, w.FirstOperation)
+		appendSeekArguments(b, w.SecondOperation)
+		return
+	}
+	b.query.Arguments = append(b.query.Arguments, w.Value.GetValue())
+	b.whereArguments++
+}
+
+// columnPaths returns the field of typeOf scanned by each selected column, as [handlerResult]
+func columnPaths(tables reflect.Value, typeOf reflect.Type, numFields int, nested []nestedTable) []columnPath {
+	paths := make([]columnPath, 0, numFields)
+	for _, field := range structFields(typeOf) {
+		if len(paths) == numFields {
+			break
+		}
+		if isIgnored(field) {
+			continue
+		}
+		if len(nested) != 0 && nested[0].column == len(paths) && derefType(field.Type) == nested[0].typeOf {
+			for _, fieldIndex := range nested[0].fieldIndexes {
+				paths = append(paths, columnPath{field: field.Index, nested: fieldIndex})
+			}
+			nested = nested[1:]
+			continue
+		}
+		if isTableRelation(tables, typeOf, field) {
+			continue
+		}
+		paths = append(paths, columnPath{field: field.Index})
+	}
+	return paths
+}
+
+func (c columnPath) typeOf(typeOf reflect.Type) reflect.Type {
+	typeOf = typeOf.FieldByIndex(c.field).Type
+	if c.nested == nil {
+		return typeOf
+	}
+	return derefType(typeOf).FieldByIndex(c.nested).Type
+}
+
+func (c columnPath) valueOf(row reflect.Value) reflect.Value {
+	valueOf := row.FieldByIndex(c.field)
+	if c.nested == nil {
+		return valueOf
+	}
+	if valueOf.Kind() == reflect.Pointer {
+		if valueOf.IsNil() {
+			return reflect.Zero(valueOf.Type().Elem().FieldByIndex(c.nested).Type)
+		}
+		valueOf = valueOf.Elem()
+	}
+	return valueOf.FieldByIndex(c.nested)
+}
+
+// cursorSignature identifies the query of a cursor, a cursor is valid only for the same result type,
+// order and where, including the arguments of the where and the having
+func cursorSignature(typeOf reflect.Type, columns []seekColumn, b *builder) (string, error) {
+	var signature strings.Builder
+	signature.WriteString(typeOf.String())
+	for _, c := range columns {
+		signature.WriteString(";" + c.field.table() + "." + c.field.getAttributeName())
+		if c.desc {
+			signature.WriteString(" desc")
+		}
+	}
+	for _, w := range []*model.Where{b.query.Where, b.filter, b.query.Having} {
+		signature.WriteString(";")
+		if err := writeWhereSignature(&signature, w); err != nil {
+			return "", err
+		}
+	}
+	for _, arguments := range [][]any{b.query.Arguments, b.havingArguments} {
+		values, err := json.Marshal(arguments)
+		if err != nil {
+			return "", err
+		}
+		signature.WriteString(";")
+		signature.Write(values)
+	}
+	return signature.String(), nil
+}
+
+// writeWhereSignature writes the operations and the columns of w, the values are on the arguments of the query
+// except the arguments of the subqueries not merged on the query, written with the where of the subquery
+func writeWhereSignature(signature *strings.Builder, w *model.Where) error {
+	if w == nil {
+		return nil
+	}
+	fmt.Fprintf(signature, "(%v %v %v.%v %v.%v", w.Type, w.Operator, w.Attribute.Table, w.Attribute.Name, w.AttributeValue.Table, w.AttributeValue.Name)
+	if w.QueryIn != nil && !w.MergedIn {
+		values, err := json.Marshal(w.QueryIn.Arguments)
+		if err != nil {
+			return err
+		}
+		signature.Write(values)
+	}
+	if w.QueryIn != nil {
+		if err := writeWhereSignature(signature, w.QueryIn.Where); err != nil {
+			return err
+		}
+	}
+	if err := writeWhereSignature(signature, w.FirstOperation); err != nil {
+		return err
+	}
+	if err := writeWhereSignature(signature, w.SecondOperation); err != nil {
+		return err
+	}
+	signature.WriteString(")")
+	return nil
+}
+
+func encodeCursor(key []byte, signature string, backward bool, row reflect.Value, paths []columnPath, columns []seekColumn) (Cursor, error) {
+	token := cursorToken{Backward: backward, Keys: make([]json.RawMessage, len(columns))}
+	for i, c := range columns {
+		var err error
+		if token.Keys[i], err = json.Marshal(paths[c.column].valueOf(row).Interface()); err != nil {
+			return "", err
+		}
+	}
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return Cursor(base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(key, signature, payload))), nil
+}
+
+func decodeCursor(key []byte, signature string, cursor Cursor) (cursorToken, error) {
+	var token cursorToken
+	encodedPayload, encodedMac, ok := strings.Cut(string(cursor), ".")
+	if !ok {
+		return token, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return token, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMac)
+	if err != nil || !hmac.Equal(mac, signCursor(key, signature, payload)) {
+		return token, ErrInvalidCursor
+	}
+	if err = json.Unmarshal(payload, &token); err != nil {
+		return token, ErrInvalidCursor
+	}
+	return token, nil
+}
+
+func signCursor(key []byte, signature string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signature))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorValues decodes the keys of a cursor to the types of the seek columns
+func cursorValues(typeOf reflect.Type, paths []columnPath, columns []seekColumn, keys []json.RawMessage) ([]any, error) {
+	if len(keys) != len(columns) {
+		return nil, ErrInvalidCursor
+	}
+	values := make([]any, len(columns))
+	for i, c := range columns {
+		value := reflect.New(paths[c.column].typeOf(typeOf))
+		if err := json.Unmarshal(keys[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
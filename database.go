@@ -4,39 +4,153 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-goe/goe/enum"
 	"github.com/go-goe/goe/model"
 )
 
-type goeMap struct {
-	mu       sync.Mutex
-	mapField map[uintptr]field
+// openDatabases holds the open databases used to resolve the mapped pointers, each database owns the fields it mapped.
+// The stored slice is never changed, Open and Close store a new one so lookups don't need a lock.
+var openDatabases atomic.Pointer[[]*DB]
+
+// registryMu serializes the stores of Open and Close on openDatabases
+var registryMu sync.Mutex
+
+// databases resolves a mapped pointer by the database that mapped it, or by the tables of the query scope
+type databases struct {
+	dbs   []*DB
+	scope *scope // tables created by Alias and With for the query, nil if the query don't use them
+}
+
+// loadFields returns the open databases, used to resolve the mapped pointers to the fields
+func loadFields() databases {
+	if dbs := openDatabases.Load(); dbs != nil {
+		return databases{dbs: *dbs}
+	}
+	return databases{}
+}
+
+// loadQueryFields returns the open databases with the tables created by Alias and With on ctx,
+// used by the select queries to resolve the fields of the aliases and common table expressions
+func loadQueryFields(ctx context.Context) databases {
+	addrMap := loadFields()
+	addrMap.scope = scopeOf(ctx)
+	return addrMap
+}
+
+// field returns the field mapped by addr on any open database or on the scope, nil if addr is not mapped
+func (dbs databases) field(addr uintptr) field {
+	for _, db := range dbs.dbs {
+		if f := db.fields[addr]; f != nil {
+			return f
+		}
+	}
+	return dbs.scope.field(addr)
 }
 
-func (am *goeMap) get(key uintptr) field {
-	am.mu.Lock()
-	defer am.mu.Unlock()
-	return am.mapField[key]
+// isTable reports whether typeOf is a table of any open database
+func (dbs databases) isTable(typeOf reflect.Type) bool {
+	return slices.ContainsFunc(dbs.dbs, func(db *DB) bool {
+		_, ok := tableField(db.tables, typeOf)
+		return ok
+	})
+}
+
+// registerDatabase adds a opened database to the openDatabases
+func registerDatabase(db *DB) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	dbs := append(slices.Clone(loadFields().dbs), db)
+	openDatabases.Store(&dbs)
+}
+
+// unregisterDatabase removes a closed database from the openDatabases
+func unregisterDatabase(db *DB) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	dbs := slices.DeleteFunc(slices.Clone(loadFields().dbs), func(d *DB) bool { return d == db })
+	openDatabases.Store(&dbs)
+}
+
+// scope holds a table created by Alias or With for the queries made with a context, each call returns
+// a context with a new scope linked to the scope of the previous calls, so a scope is never changed
+type scope struct {
+	parent *scope
+	id     int               // table id of the created table, negative so never conflict with the tables mapped on Open
+	table  any               // pointer to the created table, keeps the struct alive while the scope is used
+	alias  aliasKey          // source table and name of a alias, empty for a common table expression
+	fields map[uintptr]field // fields of the created table
+}
+
+type scopeKey struct{}
+
+// scopeOf returns the scope of ctx, nil if no table was created on ctx
+func scopeOf(ctx context.Context) *scope {
+	s, _ := ctx.Value(scopeKey{}).(*scope)
+	return s
 }
 
-func (am *goeMap) set(key uintptr, value field) {
-	am.mu.Lock()
-	defer am.mu.Unlock()
-	am.mapField[key] = value
+// withScope returns a copy of ctx with the table and the fields added to the scope of ctx
+func withScope(ctx context.Context, table any, alias aliasKey, fields map[uintptr]field) context.Context {
+	parent := scopeOf(ctx)
+	return context.WithValue(ctx, scopeKey{}, &scope{parent: parent, id: parent.nextId(), table: table, alias: alias, fields: fields})
 }
 
-func (am *goeMap) delete(key uintptr) {
-	am.mu.Lock()
-	defer am.mu.Unlock()
-	delete(am.mapField, key)
+// nextId returns the table id of the next table created on the scope
+func (s *scope) nextId() int {
+	if s == nil {
+		return -1
+	}
+	return s.id - 1
 }
 
-var addrMap *goeMap
+// field returns the field mapped by addr on the scope or on the parent scopes
+func (s *scope) field(addr uintptr) field {
+	for ; s != nil; s = s.parent {
+		if f := s.fields[addr]; f != nil {
+			return f
+		}
+	}
+	return nil
+}
 
 type DB struct {
-	driver model.Driver
+	driver    model.Driver
+	fields    map[uintptr]field    // fields mapped on Open, read only after that
+	migrator  *model.Migrator      // tables mapped on Open, used by Metadata
+	relations map[uintptr]relation // relation fields loaded by Include
+	cursorKey []byte               // key used to sign the cursors of AsCursor
+	tables    reflect.Value        // database struct, used to resolve the relation fields
+}
+
+// Return the metadata of all tables mapped by the database, with the columns,
+// struct fields, primary keys, relations and indexes.
+//
+// # Example
+//
+//	user, _ := db.Metadata().Table("User")
+//	for _, c := range user.Columns {
+//		fmt.Println(c.FieldName, c.Name, c.DataType)
+//	}
+func (db *DB) Metadata() model.Metadata {
+	return newMetadata(db.tables, db.migrator)
+}
+
+// Supports reports whether the driver renders the feature, a query using a feature
+// that the driver does not support returns a error wrapping [ErrUnsupported].
+//
+// # Example
+//
+//	if db.Supports(enum.DistinctFeature) {
+//		names, err = goe.Select[struct{ Name string }](&db.Animal.Name).Distinct().AsSlice()
+//	}
+func (db *DB) Supports(feature enum.Feature) bool {
+	return supports(db.driver, feature)
 }
 
 // Return the database stats as [sql.DBStats].
@@ -166,15 +280,7 @@ func Close(dbTarget any) error {
 		return goeDb.driver.GetDatabaseConfig().ErrorHandler(context.TODO(), err)
 	}
 
-	valueOf := reflect.ValueOf(dbTarget).Elem()
-
-	for i := range valueOf.NumField() - 1 {
-		fieldOf := valueOf.Field(i).Elem()
-		for fieldId := range fieldOf.NumField() {
-			addrMap.delete(uintptr(fieldOf.Field(fieldId).Addr().UnsafePointer()))
-		}
-	}
-
+	unregisterDatabase(goeDb)
 	return nil
 }
 
@@ -68,7 +68,7 @@ func (r remove[T]) OnTransaction(tx model.Transaction) remove[T] {
 // Removes the record by non-zero values
 func (r remove[T]) ByValue(value T) error {
 	args, valuesArgs, skip := getNonZeroFields(getArgs{
-		addrMap:   addrMap.mapField,
+		addrMap:   loadFields(),
 		tableArgs: getRemoveTableArgs(r.table),
 		value:     value})
 
@@ -99,7 +99,7 @@ func Delete[T any](table *T) stateDelete {
 // See [Delete] for examples
 func DeleteContext[T any](ctx context.Context, table *T) stateDelete {
 	var state stateDelete = createDeleteState(ctx)
-	state.builder.fields = append(state.builder.fields, getArgDelete(table, addrMap.mapField))
+	state.builder.fields = append(state.builder.fields, getArgDelete(table, loadFields()))
 	return state
 }
 
@@ -134,11 +134,14 @@ func (s stateDelete) All() error {
 
 // Where receives [model.Where] as where operations from where sub package
 func (s stateDelete) Where(o model.Where) error {
-	helperWhere(&s.builder, addrMap.mapField, &o)
+	helperWhere(&s.builder, loadFields(), &o)
 	s.builder.query.Where = &o
 	s.builder.buildSqlDelete()
 
 	driver := s.builder.fields[0].getDb().driver
+	if err := checkQuery(driver, &s.builder.query); err != nil {
+		return err
+	}
 	if s.conn == nil {
 		s.conn = driver.NewConnection()
 	}
@@ -150,18 +153,13 @@ func createDeleteState(ctx context.Context) stateDelete {
 	return stateDelete{builder: createBuilder(enum.DeleteQuery), ctx: ctx}
 }
 
-func getArgDelete(arg any, addrMap map[uintptr]field) field {
+func getArgDelete(arg any, addrMap databases) field {
 	v := reflect.ValueOf(arg)
 	if v.Kind() != reflect.Pointer {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
 
-	addr := uintptr(v.UnsafePointer())
-	if addrMap[addr] != nil {
-		return addrMap[addr]
-	}
-
-	return nil
+	return addrMap.field(uintptr(v.UnsafePointer()))
 }
 
 func getRemoveTableArgs(table any) []any {
@@ -170,11 +168,10 @@ func getRemoveTableArgs(table any) []any {
 	if valueOf.Kind() != reflect.Struct {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
+	addrMap := loadFields()
 	args := make([]any, 0, valueOf.NumField())
-	var fieldOf reflect.Value
-	for i := 0; i < valueOf.NumField(); i++ {
-		fieldOf = valueOf.Field(i)
-		if fieldOf.Kind() == reflect.Slice && fieldOf.Type().Elem().Kind() == reflect.Struct {
+	for field, fieldOf := range fieldsOf(valueOf) {
+		if isIgnored(field) || addrMap.field(uintptr(fieldOf.Addr().UnsafePointer())) == nil {
 			continue
 		}
 
@@ -0,0 +1,47 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectDistinct(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Select[struct{ Name string }](&db.Animal.Name).Distinct().AsSlice()
+	if err != nil {
+		t.Fatalf("Expected distinct, got error %v", err)
+	}
+	if q := d.lastQuery(t); !q.Distinct {
+		t.Errorf("Expected a distinct query, got %+v", q)
+	}
+
+	_, err = List(db.Animal).DistinctOn(&db.Animal.HabitatId).OrderByAsc(&db.Animal.HabitatId).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected distinct on, got error %v", err)
+	}
+	if q := d.lastQuery(t); len(q.DistinctOn) != 1 || q.DistinctOn[0].Name != `"habitat_id"` {
+		t.Errorf("Expected distinct on habitat id, got %+v", q.DistinctOn)
+	}
+
+	_, err = Select[struct{ Name string }](&db.Animal.Name).Distinct().AsPagination(1, 10)
+	if err != nil {
+		t.Fatalf("Expected distinct pagination, got error %v", err)
+	}
+	count := d.queries[len(d.queries)-2]
+	if count.FromQuery == nil || !count.FromQuery.Distinct {
+		t.Errorf("Expected the count over the distinct query, got %+v", count)
+	}
+}
+
+func TestSelectDistinctBaseline(t *testing.T) {
+	db, d := openTest(t, true)
+
+	_, err := Select[struct{ Name string }](&db.Animal.Name).Distinct().AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+	if len(d.queries) != 0 {
+		t.Errorf("Expected no query on the driver, got %v", len(d.queries))
+	}
+}
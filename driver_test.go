@@ -0,0 +1,117 @@
+package goe
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+)
+
+// testDriver records the queries without running them and supports no feature,
+// as the drivers released before model.FeatureDriver
+type testDriver struct {
+	config  model.DatabaseConfig
+	queries []model.Query
+}
+
+// featureDriver is a testDriver that supports every feature
+type featureDriver struct {
+	*testDriver
+}
+
+func (featureDriver) Supports(enum.Feature) bool { return true }
+
+func (d *testDriver) MigrateContext(context.Context, *model.Migrator) error { return nil }
+func (d *testDriver) DropTable(schema, table string) error                  { return nil }
+func (d *testDriver) DropColumn(schema, table, column string) error         { return nil }
+func (d *testDriver) RenameColumn(schema, table, oldColumn, newName string) error {
+	return nil
+}
+func (d *testDriver) RenameTable(schema, table, newName string) error { return nil }
+func (d *testDriver) Init() error                                     { return nil }
+func (d *testDriver) KeywordHandler(s string) string                  { return `"` + s + `"` }
+func (d *testDriver) NewConnection() model.Connection                 { return testConnection{d} }
+func (d *testDriver) NewTransaction(ctx context.Context, opts *sql.TxOptions) (model.Transaction, error) {
+	return nil, nil
+}
+func (d *testDriver) Stats() sql.DBStats { return sql.DBStats{} }
+func (d *testDriver) Close() error       { return nil }
+func (d *testDriver) ErrorTranslator() func(err error) error {
+	return func(err error) error { return err }
+}
+func (d *testDriver) Name() string                             { return "Test" }
+func (d *testDriver) GetDatabaseConfig() *model.DatabaseConfig { return &d.config }
+
+// lastQuery returns the last query received by the driver
+func (d *testDriver) lastQuery(t *testing.T) model.Query {
+	t.Helper()
+	if len(d.queries) == 0 {
+		t.Fatal("Expected a query, got none")
+	}
+	return d.queries[len(d.queries)-1]
+}
+
+type testConnection struct {
+	driver *testDriver
+}
+
+func (c testConnection) ExecContext(ctx context.Context, query *model.Query) error {
+	c.driver.queries = append(c.driver.queries, *query)
+	return nil
+}
+
+func (c testConnection) QueryRowContext(ctx context.Context, query *model.Query) model.Row {
+	c.driver.queries = append(c.driver.queries, *query)
+	return testRows{}
+}
+
+func (c testConnection) QueryContext(ctx context.Context, query *model.Query) (model.Rows, error) {
+	c.driver.queries = append(c.driver.queries, *query)
+	return testRows{}, nil
+}
+
+// testRows is a empty result
+type testRows struct{}
+
+func (testRows) Close() error           { return nil }
+func (testRows) Next() bool             { return false }
+func (testRows) Scan(dest ...any) error { return nil }
+
+type Animal struct {
+	Id        int
+	Name      string
+	Age       int
+	HabitatId int
+	Born      time.Time
+}
+
+type Habitat struct {
+	Id   int
+	Name string
+}
+
+type testDatabase struct {
+	Animal  *Animal
+	Habitat *Habitat
+	*DB
+}
+
+// openTest opens the test database on a driver that supports every feature,
+// or on a driver without features if baseline
+func openTest(t *testing.T, baseline bool) (*testDatabase, *testDriver) {
+	t.Helper()
+	d := &testDriver{}
+	var driver model.Driver = featureDriver{d}
+	if baseline {
+		driver = d
+	}
+	db, err := Open[testDatabase](driver)
+	if err != nil {
+		t.Fatalf("Expected open, got error %v", err)
+	}
+	t.Cleanup(func() { Close(db) })
+	return db, d
+}
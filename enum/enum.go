@@ -9,6 +9,8 @@ const (
 	OperationAttributeWhere
 	OperationIsWhere
 	OperationInWhere
+	OperationExistsWhere
+	OperationBetweenWhere
 )
 
 type QueryType uint
@@ -39,6 +41,40 @@ const (
 	_ FunctionType = iota
 	UpperFunction
 	LowerFunction
+	CoalesceFunction
+	ConcatFunction
+	SubstringFunction
+	TrimFunction
+	LengthFunction
+	AbsFunction
+	RoundFunction
+	CastFunction
+	NowFunction
+	DatePartFunction
+	DateTruncFunction
+)
+
+type WindowType uint
+
+const (
+	_ WindowType = iota
+	RowNumberWindow
+	RankWindow
+	DenseRankWindow
+	LagWindow
+	LeadWindow
+	AggregateWindow // the aggregate of the attribute over the window
+)
+
+type ArithmeticType uint
+
+const (
+	_             ArithmeticType = iota
+	AddArithmetic                // +
+	SubArithmetic                // -
+	MulArithmetic                // *
+	DivArithmetic                // /
+	ModArithmetic                // %
 )
 
 type JoinType uint
@@ -48,6 +84,18 @@ const (
 	Join
 	LeftJoin
 	RightJoin
+	FullJoin
+	CrossJoin
+)
+
+type CompoundType uint
+
+const (
+	_ CompoundType = iota
+	UnionCompound
+	UnionAllCompound
+	IntersectCompound
+	ExceptCompound
 )
 
 type OperatorType uint
@@ -68,4 +116,50 @@ const (
 	NotLike                    // NOT LIKE
 	And                        // AND
 	Or                         // OR
+	Exists                     // EXISTS
+	NotExists                  // NOT EXISTS
+	Between                    // BETWEEN $1 AND $2
+	NotBetween                 // NOT BETWEEN $1 AND $2
+	ILike                      // ILIKE, sent as LIKE of the lowercase column and value to the drivers without ILikeFeature
+	NotILike                   // NOT ILIKE, sent as NOT LIKE of the lowercase column and value to the drivers without ILikeFeature
+	Regex                      // ~, or the regex match of the database
+	NotRegex                   // !~, or the regex not match of the database
+)
+
+type RelationType uint
+
+const (
+	_ RelationType = iota
+	ManyToOneRelation
+	OneToOneRelation
+)
+
+// Feature is a part of the model added after the baseline drivers, the core checks that
+// the driver supports it before migrating or running a query, see model.FeatureDriver
+type Feature uint
+
+const (
+	_                          Feature = iota
+	ForeignKeyNameFeature              // foreign keys named by model.ManyToOneMigrate.ForeignKeyName and model.OneToOneMigrate.ForeignKeyName
+	CompositeForeignKeyFeature         // foreign keys with more than one column
+	DistinctFeature                    // DISTINCT
+	DistinctOnFeature                  // DISTINCT ON, only on the dialects with it (e.g. PostgreSQL)
+	FromQueryFeature                   // a query used as the table of the select
+	HavingFeature                      // HAVING
+	ExistsFeature                      // EXISTS and NOT EXISTS
+	SubqueryArgumentsFeature           // arguments of the subqueries of in merged on the query, see model.Where.MergedIn
+	BetweenFeature                     // BETWEEN and NOT BETWEEN
+	ILikeFeature                       // ILIKE and NOT ILIKE, without it the core sends a LIKE of the lowercase column and value
+	RegexFeature                       // regular expression match
+	AliasFeature                       // tables referenced by a alias, see model.Table.Alias
+	JoinOnFeature                      // joins by a condition
+	FullJoinFeature                    // FULL JOIN
+	CrossJoinFeature                   // CROSS JOIN
+	CompoundFeature                    // UNION, UNION ALL, INTERSECT and EXCEPT
+	WithFeature                        // common table expressions, WITH and WITH RECURSIVE
+	WindowFeature                      // window functions
+	FunctionFeature                    // model.Function, the baseline drivers render only Attribute.FunctionType of upper and lower
+	PositionFeature                    // order by and group by the position of a selected attribute
+	ExpressionFeature                  // model.Expression, arithmetic on select, where and update sets
+	CaseFeature                        // model.Case, CASE WHEN
 )
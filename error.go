@@ -13,3 +13,10 @@ var ErrBadRequest = errors.New("")
 
 // ErrNotFound occurs when the Find function returns zero results.
 var ErrNotFound = errors.New("goe: not found any element on result set")
+
+// ErrUnsupported occurs when the database struct or a query uses a feature that the driver does not implement,
+// update the driver to a version that implements [model.FeatureDriver] with the feature.
+var ErrUnsupported = errors.New("goe: unsupported by the driver")
+
+// ErrInvalidCursor occurs when AsCursor receives a cursor that was changed or created by other query.
+var ErrInvalidCursor = errors.New("goe: invalid cursor")
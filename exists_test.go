@@ -0,0 +1,57 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestWhereExists(t *testing.T) {
+	db, d := openTest(t, false)
+
+	habitats := Select[struct{ Id int }](&db.Habitat.Id).
+		Where(where.And(where.EqualsArg[int](&db.Habitat.Id, &db.Animal.HabitatId), where.Equals(&db.Habitat.Name, "forest"))).AsQuery()
+	_, err := List(db.Animal).Where(where.And(where.Equals(&db.Animal.Name, "cat"), where.Exists(habitats))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected exists, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	exists := q.Where.SecondOperation
+	if exists.QueryIn == nil || !exists.MergedIn {
+		t.Fatalf("Expected a merged subquery, got %+v", exists)
+	}
+	if len(q.Arguments) != 2 || q.Arguments[0] != "cat" || q.Arguments[1] != "forest" {
+		t.Errorf("Expected the subquery arguments merged after the outer arguments, got %v", q.Arguments)
+	}
+	if exists.QueryIn.WhereIndex != 2 {
+		t.Errorf("Expected the subquery arguments starting at 2, got %v", exists.QueryIn.WhereIndex)
+	}
+	if correlated := exists.QueryIn.Where.FirstOperation; correlated.AttributeValueTable.Name != `"animals"` {
+		t.Errorf("Expected the subquery correlated to animals, got %+v", correlated.AttributeValueTable)
+	}
+}
+
+func TestWhereInBaseline(t *testing.T) {
+	db, d := openTest(t, true)
+
+	habitats := Select[struct{ Id int }](&db.Habitat.Id).Where(where.Equals(&db.Habitat.Name, "forest")).AsQuery()
+	_, err := List(db.Animal).Where(where.And(where.Equals(&db.Animal.Name, "cat"), where.In(&db.Animal.HabitatId, habitats))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected in, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	in := q.Where.SecondOperation
+	if in.QueryIn == nil || in.MergedIn {
+		t.Fatalf("Expected a subquery not merged, got %+v", in)
+	}
+	if len(q.Arguments) != 1 || len(in.QueryIn.Arguments) != 1 {
+		t.Errorf("Expected the subquery arguments on the subquery, got %v and %v", q.Arguments, in.QueryIn.Arguments)
+	}
+
+	_, err = List(db.Animal).Where(where.Exists(habitats)).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
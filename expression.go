@@ -0,0 +1,274 @@
+package goe
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+)
+
+// createExpression maps the expression e, the result uses the table of the first column of the expression
+func createExpression(addrMap databases, e model.Expressioner) fieldSelect {
+	expression, field := buildExpression(addrMap, e)
+	if field == nil {
+		panic("goe: invalid expression. try using a expression of a database mapped field")
+	}
+	return expressionResult{
+		tableName:     field.table(),
+		sourceName:    field.getTable().Name,
+		schemaName:    field.schema(),
+		tableId:       field.getTableId(),
+		db:            field.getDb(),
+		with:          withOf(field),
+		attributeName: field.getAttributeName(),
+		expression:    expression,
+		source:        e}
+}
+
+// buildExpression maps the operands of e as attributes, functions, expressions and arguments,
+// returns the expression and the first column used by the expression
+func buildExpression(addrMap databases, e model.Expressioner) (*model.Expression, field) {
+	left, right := e.GetOperands()
+	expression := &model.Expression{Operator: e.GetOperator()}
+
+	var column, rightColumn field
+	expression.Left, column = buildOperand(addrMap, left)
+	expression.Right, rightColumn = buildOperand(addrMap, right)
+	if column == nil {
+		column = rightColumn
+	}
+	return expression, column
+}
+
+func buildOperand(addrMap databases, operand any) (model.Operand, field) {
+	switch o := operand.(type) {
+	case model.Expressioner:
+		// a expression without operator is a argument
+		if o.GetOperator() == 0 {
+			return model.Operand{Value: o.GetValue()}, nil
+		}
+		expression, column := buildExpression(addrMap, o)
+		return model.Operand{Expression: expression}, column
+	case model.FunctionType:
+		function, column := buildFunction(addrMap, o)
+		attribute := model.Attribute{Function: function}
+		if column != nil {
+			attribute.Table, attribute.Name = column.table(), column.getAttributeName()
+		}
+		attribute.FunctionType = functionType(function)
+		return model.Operand{Attribute: &attribute}, column
+	}
+
+	valueOf := reflect.ValueOf(operand)
+	if valueOf.Kind() == reflect.Pointer {
+		if f := addrMap.field(uintptr(valueOf.UnsafePointer())); f != nil {
+			return model.Operand{Attribute: &model.Attribute{Table: f.table(), Name: f.getAttributeName()}}, f
+		}
+	}
+	panic("goe: invalid expression. try sending a pointer to a database mapped field")
+}
+
+// expressionArguments appends the arguments of the operands of e to arguments, on the order of the operands
+func expressionArguments(arguments []any, e *model.Expression) []any {
+	if e == nil {
+		return arguments
+	}
+	arguments = operandArguments(arguments, e.Left)
+	return operandArguments(arguments, e.Right)
+}
+
+func operandArguments(arguments []any, o model.Operand) []any {
+	switch {
+	case o.Attribute != nil:
+		return functionArguments(arguments, o.Attribute.Function)
+	case o.Expression != nil:
+		return expressionArguments(arguments, o.Expression)
+	}
+	return append(arguments, o.Value)
+}
+
+// functionArguments appends the values of the arguments of f and of the nested functions to arguments,
+// on the order of the arguments
+func functionArguments(arguments []any, f *model.Function) []any {
+	if f == nil {
+		return arguments
+	}
+	for _, a := range f.Arguments {
+		switch {
+		case a.Function != nil:
+			arguments = functionArguments(arguments, a.Function)
+		case a.Attribute == nil && a.Keyword == "":
+			arguments = append(arguments, a.Value)
+		}
+	}
+	return arguments
+}
+
+// attributeArguments appends the arguments of the expression or of the function of a to arguments
+func attributeArguments(arguments []any, a model.Attribute) []any {
+	arguments = expressionArguments(arguments, a.Expression)
+	return functionArguments(arguments, a.Function)
+}
+
+// createCase maps the conditions and values of c, the tables of the conditions are not added to the query.
+// The result uses the table of the first column of the values or of the conditions,
+// or has no table until is set by setFunctionTables
+func createCase(addrMap databases, c model.Caser) caseResult {
+	conditions, values := c.GetWhens()
+	if len(conditions) == 0 {
+		panic("goe: invalid case. try adding a when to the case")
+	}
+
+	b := builder{tables: make(map[int]bool)}
+	result := caseResult{source: c, c: &model.Case{Whens: make([]model.When, len(conditions))}}
+	var column, conditionColumn field
+	for i := range conditions {
+		condition := conditions[i]
+		helperWhere(&b, addrMap, &condition)
+		then, f := buildCaseValue(addrMap, values[i])
+		b.query.Arguments = operandArguments(b.query.Arguments, then)
+		result.c.Whens[i] = model.When{Condition: &condition, Then: then}
+		if column == nil {
+			column = f
+		}
+		if conditionColumn == nil {
+			conditionColumn = whereColumn(addrMap, &condition)
+		}
+	}
+	if value, ok := c.GetElse(); ok {
+		elseValue, f := buildCaseValue(addrMap, value)
+		b.query.Arguments = operandArguments(b.query.Arguments, elseValue)
+		result.c.Else = &elseValue
+		if column == nil {
+			column = f
+		}
+	}
+	result.arguments = b.query.Arguments
+
+	if column == nil {
+		column = conditionColumn
+	}
+	if column != nil {
+		result.tableName, result.sourceName, result.schemaName = column.table(), column.getTable().Name, column.schema()
+		result.tableId, result.db, result.with = column.getTableId(), column.getDb(), withOf(column)
+	}
+	return result
+}
+
+// whereColumn returns the column of the first operation of w, nil if the operation is not on a column
+func whereColumn(addrMap databases, w *model.Where) field {
+	if w.Type == enum.LogicalWhere {
+		return whereColumn(addrMap, w.FirstOperation)
+	}
+	valueOf := reflect.ValueOf(w.Arg)
+	if valueOf.Kind() != reflect.Pointer {
+		return nil
+	}
+	return addrMap.field(uintptr(valueOf.UnsafePointer()))
+}
+
+// buildCaseValue maps a value of a case as a operand, the values that are not fields,
+// functions or expressions are arguments
+func buildCaseValue(addrMap databases, value any) (model.Operand, field) {
+	switch value.(type) {
+	case model.Expressioner, model.FunctionType:
+		return buildOperand(addrMap, value)
+	}
+	valueOf := reflect.ValueOf(value)
+	if valueOf.Kind() == reflect.Pointer && addrMap.field(uintptr(valueOf.UnsafePointer())) != nil {
+		return buildOperand(addrMap, value)
+	}
+	return model.Operand{Value: value}, nil
+}
+
+// indexAttributes sets the position of the arguments of the cases of the selected attributes,
+// the arguments of the attributes start at index. Returns the position after the arguments of the attributes
+func indexAttributes(q *model.Query, index int) int {
+	start := index
+	if slices.ContainsFunc(q.Attributes, func(a model.Attribute) bool { return a.Case != nil }) {
+		q.Attributes = slices.Clone(q.Attributes)
+	}
+	for i := range q.Attributes {
+		index += len(attributeArguments(nil, q.Attributes[i]))
+		if q.Attributes[i].Case == nil {
+			continue
+		}
+		c := *q.Attributes[i].Case
+		c.Whens = slices.Clone(c.Whens)
+		for j := range c.Whens {
+			c.Whens[j].Index = index
+			index = indexSubqueries(c.Whens[j].Condition, index)
+			index += len(operandArguments(nil, c.Whens[j].Then))
+		}
+		if c.Else != nil {
+			index += len(operandArguments(nil, *c.Else))
+		}
+		q.Attributes[i].Case = &c
+	}
+	if index != start {
+		q.AttributeIndex = start
+	}
+	return index
+}
+
+// selectedPosition returns the position starting at 1 of arg on the selected cases, expressions
+// and functions with arguments, zero if arg is not selected
+func (b *builder) selectedPosition(arg any) int {
+	for i, f := range b.fieldsSelect {
+		switch r := f.(type) {
+		case caseResult:
+			if r.source == arg {
+				return i + 1
+			}
+		case expressionResult:
+			if r.source == arg {
+				return i + 1
+			}
+		case functionResult:
+			if r.source != nil && r.source == arg {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// appendAttribute appends the arguments of the expression or of the function of a where, before the value of the where
+func appendAttribute(b *builder, a model.Attribute) {
+	n := len(b.query.Arguments)
+	b.query.Arguments = attributeArguments(b.query.Arguments, a)
+	b.whereArguments += len(b.query.Arguments) - n
+}
+
+// buildAttributesArguments sets the arguments of the expressions, functions and cases of the selected attributes
+// before the arguments of the joins, after the arguments of the common table expressions added by buildWiths
+func (b *builder) buildAttributesArguments() {
+	var arguments []any
+	for i, f := range b.fieldsSelect {
+		if c, ok := f.(caseResult); ok {
+			arguments = append(arguments, c.arguments...)
+			continue
+		}
+		arguments = attributeArguments(arguments, b.query.Attributes[i])
+	}
+	if len(arguments) == 0 {
+		return
+	}
+	b.query.Arguments = append(arguments, b.query.Arguments...)
+	reindexQuery(&b.query, 1)
+}
+
+// setsArguments returns the arguments of the update, the values of the sets and
+// the arguments of the expressions on the order of the sets
+func (b *builder) setsArguments() []any {
+	arguments := make([]any, 0, len(b.sets))
+	for i := range b.sets {
+		if b.sets[i].expression != nil {
+			arguments = expressionArguments(arguments, b.sets[i].expression)
+			continue
+		}
+		arguments = append(arguments, b.sets[i].value)
+	}
+	return arguments
+}
@@ -0,0 +1,67 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/expr"
+	"github.com/go-goe/goe/query/update"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestSelectExpression(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Select[struct{ Total int }](expr.Mul(&db.Animal.Age, expr.Add(&db.Animal.Id, expr.Argument(2)))).
+		Where(where.Greater(expr.Sub(&db.Animal.Age, &db.Animal.Id), expr.Argument(0))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected expression, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	e := q.Attributes[0].Expression
+	if e == nil || e.Operator != enum.MulArithmetic || e.Left.Attribute == nil || e.Left.Attribute.Name != `"age"` {
+		t.Fatalf("Expected age multiplied, got %+v", q.Attributes[0])
+	}
+	if e.Right.Expression == nil || e.Right.Expression.Operator != enum.AddArithmetic || e.Right.Expression.Right.Value != 2 {
+		t.Errorf("Expected id plus 2 as right operand, got %+v", e.Right)
+	}
+	w := q.Where.Attribute.Expression
+	if w == nil || w.Operator != enum.SubArithmetic || w.Right.Attribute == nil || w.Right.Attribute.Name != `"id"` {
+		t.Errorf("Expected where age minus id, got %+v", q.Where.Attribute)
+	}
+	if q.Arguments[len(q.Arguments)-1] != 0 {
+		t.Errorf("Expected where greater than 0, got %v", q.Arguments)
+	}
+}
+
+func TestUpdateExpression(t *testing.T) {
+	db, d := openTest(t, false)
+
+	err := Update(db.Animal).Sets(update.Expression(&db.Animal.Age, expr.Add(&db.Animal.Age, expr.Argument(1)))).
+		Where(where.Equals(&db.Animal.Id, 2))
+	if err != nil {
+		t.Fatalf("Expected update with expression, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	e := q.Attributes[0].Expression
+	if q.Attributes[0].Name != `"age"` || e == nil || e.Operator != enum.AddArithmetic || e.Left.Attribute.Name != `"age"` {
+		t.Errorf("Expected set age = age + 1, got %+v", q.Attributes[0])
+	}
+
+	err = Update(db.Animal).Sets(update.Expression(&db.Animal.Id, expr.Add(&db.Habitat.Id, expr.Argument(1)))).All()
+	if err == nil {
+		t.Error("Expected error for a expression of other table")
+	}
+}
+
+func TestSelectExpressionBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	_, err := Select[struct{ Total int }](expr.Add(&db.Animal.Age, expr.Argument(1))).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package goe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+	"github.com/go-goe/goe/utils"
+)
+
+// supports reports whether the driver renders the feature,
+// a driver that does not implement [model.FeatureDriver] supports none
+func supports(driver model.Driver, feature enum.Feature) bool {
+	fd, ok := driver.(model.FeatureDriver)
+	return ok && fd.Supports(feature)
+}
+
+// checkFeature returns ErrUnsupported with what is using the feature if the driver does not support it
+func checkFeature(driver model.Driver, feature enum.Feature, usage string) error {
+	if supports(driver, feature) {
+		return nil
+	}
+	return fmt.Errorf("%w: %v needs a %v driver that supports it", ErrUnsupported, usage, driver.Name())
+}
+
+// checkMigrator returns a error for each feature used by the migrator tables that the driver does not support,
+// the old drivers ignore the new fields of the model and would create a different schema
+func checkMigrator(driver model.Driver, migrator *model.Migrator) error {
+	var errs []error
+	for _, t := range migrator.Tables {
+		for _, mto := range t.ManyToOnes {
+			if len(mto.Composite) != 0 {
+				errs = append(errs, checkFeature(driver, enum.CompositeForeignKeyFeature, fmt.Sprintf("composite foreign key %q on %q", mto.FieldName, t.StructName)))
+			}
+			if !defaultForeignKeyName(t.Name, mto.Name, mto.ForeignKeyName) {
+				errs = append(errs, checkFeature(driver, enum.ForeignKeyNameFeature, fmt.Sprintf("foreign key name %q on %q", mto.ForeignKeyName, t.StructName)))
+			}
+		}
+		for _, oto := range t.OneToOnes {
+			if !defaultForeignKeyName(t.Name, oto.Name, oto.ForeignKeyName) {
+				errs = append(errs, checkFeature(driver, enum.ForeignKeyNameFeature, fmt.Sprintf("foreign key name %q on %q", oto.ForeignKeyName, t.StructName)))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// defaultForeignKeyName reports whether name is the foreign key name of [utils.DefaultNamingStrategy],
+// the name used by the drivers that ignore the ForeignKeyName of the relations
+func defaultForeignKeyName(table, column, name string) bool {
+	return name == utils.DefaultNamingStrategy{}.ForeignKeyName(table, column)
+}
+
+// checkQuery returns ErrUnsupported with the first feature used by the query that the driver does not support
+func checkQuery(driver model.Driver, query *model.Query) error {
+	var err error
+	queryFeatures(query, func(feature enum.Feature, usage string) {
+		if err == nil {
+			err = checkFeature(driver, feature, usage)
+		}
+	})
+	return err
+}
+
+// queryFeatures calls use with each feature used by the query and the subqueries
+func queryFeatures(query *model.Query, use func(feature enum.Feature, usage string)) {
+	for i := range query.With {
+		use(enum.WithFeature, "WITH")
+		queryFeatures(&query.With[i].Query, use)
+	}
+	for i := range query.Attributes {
+		attributeFeatures(&query.Attributes[i], use)
+	}
+	if query.Distinct {
+		use(enum.DistinctFeature, "DISTINCT")
+	}
+	if len(query.DistinctOn) != 0 {
+		use(enum.DistinctOnFeature, "DISTINCT ON")
+	}
+	if query.FromQuery != nil {
+		use(enum.FromQueryFeature, "select from a subquery")
+		queryFeatures(query.FromQuery, use)
+	}
+	for i := range query.DistinctOn {
+		attributeFeatures(&query.DistinctOn[i], use)
+	}
+	for _, t := range query.Tables {
+		if t.Alias != "" {
+			use(enum.AliasFeature, "table alias")
+		}
+	}
+	for _, j := range query.Joins {
+		if j.Table.Alias != "" {
+			use(enum.AliasFeature, "table alias")
+		}
+		switch j.JoinOperation {
+		case enum.FullJoin:
+			use(enum.FullJoinFeature, "FULL JOIN")
+		case enum.CrossJoin:
+			use(enum.CrossJoinFeature, "CROSS JOIN")
+		}
+		if j.On != nil {
+			use(enum.JoinOnFeature, "join by a condition")
+			whereFeatures(j.On, use)
+		}
+	}
+	whereFeatures(query.Where, use)
+	if query.Having != nil {
+		use(enum.HavingFeature, "HAVING")
+		whereFeatures(query.Having, use)
+	}
+	for i := range query.GroupBy {
+		if query.GroupBy[i].Position != 0 {
+			use(enum.PositionFeature, "group by position")
+		}
+		attributeFeatures(&query.GroupBy[i].Attribute, use)
+	}
+	for i := range query.OrderBy {
+		if query.OrderBy[i].Position != 0 {
+			use(enum.PositionFeature, "order by position")
+		}
+		attributeFeatures(&query.OrderBy[i].Attribute, use)
+	}
+	for i := range query.Compound {
+		use(enum.CompoundFeature, "compound query")
+		queryFeatures(&query.Compound[i].Query, use)
+	}
+}
+
+// whereFeatures calls use with each feature used by the where operations and the subqueries
+func whereFeatures(where *model.Where, use func(feature enum.Feature, usage string)) {
+	if where == nil {
+		return
+	}
+	if where.Type == enum.OperationExistsWhere {
+		use(enum.ExistsFeature, "EXISTS")
+	}
+	switch where.Operator {
+	case enum.Between, enum.NotBetween:
+		use(enum.BetweenFeature, "BETWEEN")
+	case enum.Regex, enum.NotRegex:
+		use(enum.RegexFeature, "regular expression match")
+	}
+	attributeFeatures(&where.Attribute, use)
+	attributeFeatures(&where.AttributeValue, use)
+	if where.QueryIn != nil {
+		queryFeatures(where.QueryIn, use)
+	}
+	whereFeatures(where.FirstOperation, use)
+	whereFeatures(where.SecondOperation, use)
+}
+
+// attributeFeatures calls use with each feature used by the attribute
+func attributeFeatures(attribute *model.Attribute, use func(feature enum.Feature, usage string)) {
+	if attribute.Window != nil {
+		use(enum.WindowFeature, "window function")
+	}
+	if attribute.Function != nil && !legacyFunction(attribute) {
+		use(enum.FunctionFeature, "function")
+	}
+	if attribute.Expression != nil {
+		use(enum.ExpressionFeature, "arithmetic expression")
+		expressionFeatures(attribute.Expression, use)
+	}
+	if attribute.Case != nil {
+		use(enum.CaseFeature, "CASE")
+		for _, w := range attribute.Case.Whens {
+			whereFeatures(w.Condition, use)
+			operandFeatures(w.Then, use)
+		}
+		if attribute.Case.Else != nil {
+			operandFeatures(*attribute.Case.Else, use)
+		}
+	}
+}
+
+// expressionFeatures calls use with each feature used by the operands of the expression
+func expressionFeatures(expression *model.Expression, use func(feature enum.Feature, usage string)) {
+	operandFeatures(expression.Left, use)
+	operandFeatures(expression.Right, use)
+}
+
+// operandFeatures calls use with each feature used by the attribute or the expression of the operand
+func operandFeatures(operand model.Operand, use func(feature enum.Feature, usage string)) {
+	if operand.Attribute != nil {
+		attributeFeatures(operand.Attribute, use)
+	}
+	if operand.Expression != nil {
+		use(enum.ExpressionFeature, "arithmetic expression")
+		expressionFeatures(operand.Expression, use)
+	}
+}
+
+// legacyFunction reports whether the function of the attribute is rendered by the baseline drivers,
+// upper or lower of a column set on the deprecated FunctionType
+func legacyFunction(attribute *model.Attribute) bool {
+	switch attribute.FunctionType {
+	case enum.UpperFunction, enum.LowerFunction:
+		return attribute.FunctionType == attribute.Function.Type
+	}
+	return false
+}
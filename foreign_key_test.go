@@ -0,0 +1,53 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+)
+
+type Order struct {
+	Code     string `goe:"pk"`
+	TenantId string `goe:"pk"`
+	Customer string
+}
+
+type OrderLine struct {
+	Id        int
+	OrderCode string `goe:"fk:Order.Code"`
+	TenantId  string `goe:"fk:Order.TenantId"`
+	Product   string
+}
+
+type orderDatabase struct {
+	Order     *Order
+	OrderLine *OrderLine
+	*DB
+}
+
+func TestCompositeForeignKey(t *testing.T) {
+	_, err := Open[orderDatabase](&testDriver{})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Expected ErrUnsupported on a driver without features, got %v", err)
+	}
+
+	d := &testDriver{}
+	db, err := Open[orderDatabase](featureDriver{d})
+	if err != nil {
+		t.Fatalf("Expected open, got error %v", err)
+	}
+	defer Close(db)
+
+	_, err = Select[struct{ Customer, Product string }](&db.Order.Customer, &db.OrderLine.Product).
+		Join(&db.OrderLine.OrderCode, &db.Order.Code).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected select, got error %v", err)
+	}
+	joins := d.lastQuery(t).Joins
+	if len(joins) != 1 || len(joins[0].Composite) != 1 {
+		t.Fatalf("Expected a join with one composite column, got %+v", joins)
+	}
+	c := joins[0].Composite[0]
+	if c.FirstArgument.Name != `"tenant_id"` || c.SecondArgument.Name != `"tenant_id"` || c.FirstArgument.Table == c.SecondArgument.Table {
+		t.Errorf("Expected the tenant id of orders and order lines as composite column, got %+v", c)
+	}
+}
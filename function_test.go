@@ -0,0 +1,88 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+	"github.com/go-goe/goe/query/function"
+	"github.com/go-goe/goe/query/where"
+)
+
+// upper is a function of a single field without arguments, as the functions made before model.FunctionArguments
+type upper struct {
+	field *string
+}
+
+func (u upper) GetType() enum.FunctionType { return enum.UpperFunction }
+
+func (u upper) Attribute(b model.Body) model.Attribute {
+	return model.Attribute{Table: b.Table, Name: b.Name, FunctionType: enum.UpperFunction}
+}
+
+func (u upper) GetField() any { return u.field }
+
+func TestSelectFunction(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Select[struct{ Initial string }](function.ToUpper(function.Substring(&db.Animal.Name, 1, 1))).
+		Where(where.Equals(function.Length(&db.Animal.Name), function.Argument[int64](3))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected function, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	f := q.Attributes[0].Function
+	if f == nil || f.Type != enum.UpperFunction || len(f.Arguments) != 1 || f.Arguments[0].Function == nil {
+		t.Fatalf("Expected upper of a function, got %+v", q.Attributes[0])
+	}
+	substring := f.Arguments[0].Function
+	if len(substring.Arguments) != 3 || substring.Arguments[0].Attribute.Name != `"name"` || substring.Arguments[1].Value != 1 {
+		t.Errorf("Expected substring of name from 1, got %+v", substring.Arguments)
+	}
+	if q.Where.Attribute.Function == nil || q.Where.Attribute.Function.Type != enum.LengthFunction || q.Arguments[len(q.Arguments)-1] != int64(3) {
+		t.Errorf("Expected where length equals 3, got %+v %v", q.Where.Attribute, q.Arguments)
+	}
+
+	_, err = Select[struct{ Name string }](&upper{&db.Animal.Name}).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected function without arguments, got error %v", err)
+	}
+	if f = d.lastQuery(t).Attributes[0].Function; len(f.Arguments) != 1 || f.Arguments[0].Attribute.Name != `"name"` {
+		t.Errorf("Expected the field as argument, got %+v", f)
+	}
+
+	attribute := function.Substring(&db.Animal.Name, 2, 3).Attribute(model.Body{Table: `"animals"`, Name: `"name"`})
+	if f = attribute.Function; len(f.Arguments) != 3 || f.Arguments[0].Attribute.Name != `"name"` || f.Arguments[2].Value != 3 {
+		t.Errorf("Expected the arguments on the attribute of the function, got %+v", f)
+	}
+
+	_, err = Select[struct{ Month float64 }](function.DatePart(function.Month, &db.Animal.Born)).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected date part, got error %v", err)
+	}
+	if f = d.lastQuery(t).Attributes[0].Function; f.Arguments[0].Keyword != "month" {
+		t.Errorf("Expected the month keyword, got %+v", f.Arguments)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a invalid date part")
+		}
+	}()
+	Select[struct{ Part float64 }](function.DatePart("week); --", &db.Animal.Born)).AsSlice()
+}
+
+func TestSelectFunctionBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	_, err := Select[struct{ Name string }](function.ToUpper(&db.Animal.Name)).AsSlice()
+	if err != nil {
+		t.Errorf("Expected upper of a column on a driver without features, got error %v", err)
+	}
+	_, err = Select[struct{ Name string }](function.Trim(&db.Animal.Name)).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -2,8 +2,11 @@ package goe
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"iter"
 	"reflect"
 	"slices"
 	"strings"
@@ -12,10 +15,6 @@ import (
 	"github.com/go-goe/goe/utils"
 )
 
-func init() {
-	addrMap = &goeMap{mapField: make(map[uintptr]field)}
-}
-
 // Open opens a database connection
 //
 // # Example
@@ -40,7 +39,7 @@ func Open[T any](driver model.Driver) (*T, error) {
 		return nil, errors.New("goe: invalid database, last struct field needs to be goe.DB")
 	}
 
-	dbTarget := new(DB)
+	dbTarget := &DB{fields: make(map[uintptr]field), cursorKey: newCursorKey(driver.GetDatabaseConfig().CursorSecret)}
 	valueOf.Field(dbId).Set(reflect.ValueOf(dbTarget))
 
 	// set value for Fields
@@ -55,7 +54,9 @@ func Open[T any](driver model.Driver) (*T, error) {
 			}
 		}
 	}
+	dbTarget.tables = valueOf
 	var schemas []string
+	var errs []error
 	tableId := 0
 	// init Fields
 	for f := range dbId {
@@ -64,26 +65,30 @@ func Open[T any](driver model.Driver) (*T, error) {
 			schemas = append(schemas, schema)
 			for i := range valueOf.Field(f).Elem().NumField() {
 				tableId += i + 1
-				err = initField(&schema, valueOf, valueOf.Field(f).Elem().Field(i).Elem(), dbTarget, tableId, driver)
-				if err != nil {
-					return nil, err
-				}
+				errs = append(errs, initField(&schema, valueOf, valueOf.Field(f).Elem().Field(i).Elem(), dbTarget, tableId, driver))
 			}
 			continue
 		}
 		tableId++
-		err = initField(nil, valueOf, valueOf.Field(f).Elem(), dbTarget, tableId, driver)
-		if err != nil {
-			return nil, err
-		}
+		errs = append(errs, initField(nil, valueOf, valueOf.Field(f).Elem(), dbTarget, tableId, driver))
+	}
+	dbTarget.relations, err = initRelations(valueOf, dbTarget)
+	errs = append(errs, err)
+	// the migration checks the relations and indexes of all tables
+	dbTarget.migrator = migrateFrom(db, driver)
+	errs = append(errs, dbTarget.migrator.Error, checkMigrator(driver, dbTarget.migrator))
+	if err = errors.Join(errs...); err != nil {
+		return nil, err
 	}
 	driver.GetDatabaseConfig().SetSchemas(schemas)
+	dbTarget.driver = driver
+	registerDatabase(dbTarget)
 	if ic := driver.GetDatabaseConfig().InitCallback(); ic != nil {
 		if err = ic(); err != nil {
+			unregisterDatabase(dbTarget)
 			return nil, err
 		}
 	}
-	dbTarget.driver = driver
 	return db, nil
 }
 
@@ -117,6 +122,7 @@ type body struct {
 	migrate     *infosMigrate // used on migrate
 	schemasMap  map[string]*string
 	fieldId     int
+	structField reflect.StructField // field being mapped, the index is relative to the table struct
 	driver      model.Driver
 	nullable    bool
 	schema      *string
@@ -125,7 +131,7 @@ type body struct {
 
 func skipPrimaryKey[T comparable](slice []T, value T, tables reflect.Value, field reflect.StructField) bool {
 	if slices.Contains(slice, value) {
-		table, prefix := foreignKeyNamePattern(tables, field.Name)
+		table, prefix := getForeignKey(tables, field)
 		if table == "" && prefix == "" {
 			return true
 		}
@@ -134,87 +140,127 @@ func skipPrimaryKey[T comparable](slice []T, value T, tables reflect.Value, fiel
 }
 
 func initField(schema *string, tables reflect.Value, valueOf reflect.Value, db *DB, tableId int, driver model.Driver) error {
-	pks, fieldIds, err := getPk(db, schema, valueOf.Type(), tableId, driver)
+	if err := checkEmbedded(valueOf.Type()); err != nil {
+		return err
+	}
+	pks, fieldIds, err := getPk(db, schema, tables, valueOf.Type(), tableId, driver)
 	if err != nil {
 		return err
 	}
 
-	var field reflect.StructField
+	var fieldOf reflect.Value
+	var errs []error
 
-	for fieldId := range valueOf.NumField() {
-		field = valueOf.Type().Field(fieldId)
-		if skipPrimaryKey(fieldIds, fieldId, tables, field) {
+	for fieldId, field := range structFields(valueOf.Type()) {
+		if isIgnored(field) || skipPrimaryKey(fieldIds, fieldId, tables, field) {
+			continue
+		}
+		if err = checkField(valueOf.Type(), field); err != nil {
+			errs = append(errs, err)
 			continue
 		}
-		switch valueOf.Field(fieldId).Kind() {
+		if isRelation(tables, field) {
+			continue
+		}
+		fieldOf = valueOf.FieldByIndex(field.Index)
+		switch fieldOf.Kind() {
 		case reflect.Slice:
 			err = handlerSlice(body{
-				fieldTypeOf: valueOf.Field(fieldId).Type().Elem(),
+				fieldTypeOf: fieldOf.Type().Elem(),
 				valueOf:     valueOf,
 				typeOf:      valueOf.Type(),
 				tables:      tables,
 				fieldId:     fieldId,
+				structField: field,
 				schema:      schema,
 				mapp: &infosMap{
 					pks:     pks,
 					db:      db,
 					tableId: tableId,
-					addr:    uintptr(valueOf.Field(fieldId).Addr().UnsafePointer()),
+					addr:    uintptr(fieldOf.Addr().UnsafePointer()),
 				},
 				driver: driver,
 			}, helperAttribute)
-			if err != nil {
-				return err
-			}
+			errs = append(errs, err)
 		case reflect.Struct:
 			handlerStruct(body{
 				fieldId:     fieldId,
+				structField: field,
 				driver:      driver,
-				fieldTypeOf: valueOf.Field(fieldId).Type(),
+				fieldTypeOf: fieldOf.Type(),
 				valueOf:     valueOf,
 				schema:      schema,
 				mapp: &infosMap{
 					pks:     pks,
 					db:      db,
 					tableId: tableId,
-					addr:    uintptr(valueOf.Field(fieldId).Addr().UnsafePointer()),
+					addr:    uintptr(fieldOf.Addr().UnsafePointer()),
 				},
 			}, newAttr)
 		case reflect.Pointer:
-			helperAttribute(body{
-				fieldId:  fieldId,
-				driver:   driver,
-				nullable: true,
-				tables:   tables,
-				valueOf:  valueOf,
-				typeOf:   valueOf.Type(),
-				schema:   schema,
+			err = helperAttribute(body{
+				fieldId:     fieldId,
+				structField: field,
+				driver:      driver,
+				nullable:    true,
+				tables:      tables,
+				valueOf:     valueOf,
+				typeOf:      valueOf.Type(),
+				schema:      schema,
 				mapp: &infosMap{
 					pks:     pks,
 					db:      db,
 					tableId: tableId,
-					addr:    uintptr(valueOf.Field(fieldId).Addr().UnsafePointer()),
+					addr:    uintptr(fieldOf.Addr().UnsafePointer()),
 				},
 			})
+			errs = append(errs, err)
 		default:
-			helperAttribute(body{
-				fieldId: fieldId,
-				driver:  driver,
-				tables:  tables,
-				valueOf: valueOf,
-				typeOf:  valueOf.Type(),
-				schema:  schema,
+			err = helperAttribute(body{
+				fieldId:     fieldId,
+				structField: field,
+				driver:      driver,
+				tables:      tables,
+				valueOf:     valueOf,
+				typeOf:      valueOf.Type(),
+				schema:      schema,
 				mapp: &infosMap{
 					pks:     pks,
 					db:      db,
 					tableId: tableId,
-					addr:    uintptr(valueOf.Field(fieldId).Addr().UnsafePointer()),
+					addr:    uintptr(fieldOf.Addr().UnsafePointer()),
 				},
 			})
+			errs = append(errs, err)
 		}
 	}
 	for i := range pks {
-		addrMap.set(uintptr(valueOf.Field(fieldIds[i]).Addr().UnsafePointer()), pks[i])
+		db.fields[uintptr(valueOf.FieldByIndex(pks[i].fieldIndex).Addr().UnsafePointer())] = pks[i]
+	}
+	return errors.Join(errs...)
+}
+
+// checkField returns a error if field can't be mapped to a column or a relation,
+// the slices other than []byte are not columns and are skipped if they are not a has-many relation
+func checkField(typeOf reflect.Type, field reflect.StructField) error {
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Errorf("goe: struct %q field %q have a unsupported kind %q. try ignoring the field with the tag \"-\"", typeOf.Name(), field.Name, fieldType.Kind())
+	}
+	return nil
+}
+
+// checkEmbedded returns a error if typeOf embeds a pointer to struct,
+// the fields of a embedded pointer are not promoted to the table
+func checkEmbedded(typeOf reflect.Type) error {
+	for _, field := range structFields(typeOf) {
+		if field.Anonymous && field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct && !isIgnored(field) {
+			return fmt.Errorf("goe: struct %q field %q is a embedded pointer. try embedding the struct %q without pointer", typeOf.Name(), field.Name, field.Type.Elem().Name())
+		}
 	}
 	return nil
 }
@@ -234,15 +280,16 @@ func handlerSlice(b body, helper func(b body) error) error {
 func newAttr(b body) error {
 	at := createAtt(
 		b.mapp.db,
-		b.valueOf.Type().Field(b.fieldId).Name,
+		getColumnName(b.structField, b.driver),
 		b.schema,
 		b.mapp.pks[0].tableName,
 		b.mapp.tableId,
 		b.fieldId,
-		getTagValue(b.valueOf.Type().Field(b.fieldId).Tag.Get("goe"), "default:") != "",
+		b.structField.Index,
+		getTagValue(b.structField.Tag.Get("goe"), "default:") != "",
 		b.driver,
 	)
-	addrMap.set(b.mapp.addr, at)
+	b.mapp.db.fields[b.mapp.addr] = at
 	return nil
 }
 
@@ -257,7 +304,7 @@ func getPks(typeOf reflect.Type) []reflect.StructField {
 	return pks
 }
 
-func getPk(db *DB, schema *string, typeOf reflect.Type, tableId int, driver model.Driver) ([]pk, []int, error) {
+func getPk(db *DB, schema *string, tables reflect.Value, typeOf reflect.Type, tableId int, driver model.Driver) ([]pk, []int, error) {
 	var pks []pk
 	var fieldIds []int
 	var fieldId int
@@ -271,7 +318,7 @@ func getPk(db *DB, schema *string, typeOf reflect.Type, tableId int, driver mode
 	fieldIds = make([]int, len(fields))
 	for i := range fields {
 		fieldId = getFieldId(typeOf, fields[i].Name)
-		pks[i] = createPk(db, schema, typeOf.Name(), fields[i].Name, isReturningId(fields[i]), tableId, fieldId, driver)
+		pks[i] = createPk(db, schema, getTableName(tables, typeOf, driver), getColumnName(fields[i], driver), isReturningId(fields[i]), tableId, fieldId, fields[i].Index, driver)
 		fieldIds[i] = fieldId
 	}
 
@@ -279,8 +326,8 @@ func getPk(db *DB, schema *string, typeOf reflect.Type, tableId int, driver mode
 }
 
 func getFieldId(typeOf reflect.Type, fieldName string) int {
-	for i := 0; i < typeOf.NumField(); i++ {
-		if typeOf.Field(i).Name == fieldName {
+	for i, field := range structFields(typeOf) {
+		if field.Name == fieldName {
 			return i
 		}
 	}
@@ -296,7 +343,7 @@ func isManyToOne(b body, createMany func(b body, typeOf reflect.Type) any, creat
 	if fieldByName.IsValid() {
 		for i := 0; i < fieldByName.NumField(); i++ {
 			// check if there is a slice to typeOf
-			if fieldByName.Field(i).Kind() == reflect.Slice {
+			if fieldByName.Field(i).Kind() == reflect.Slice && !isIgnored(fieldByName.Type().Field(i)) {
 				if fieldByName.Field(i).Type().Elem().Name() == b.typeOf.Name() {
 					return createMany(b, fieldByName.Type())
 				}
@@ -333,9 +380,9 @@ func primaryKeys(str reflect.Type) (pks []reflect.StructField) {
 func fieldsByTags(tag string, str reflect.Type) (f []reflect.StructField) {
 	f = make([]reflect.StructField, 0)
 
-	for i := 0; i < str.NumField(); i++ {
-		if strings.Contains(str.Field(i).Tag.Get("goe"), tag) {
-			f = append(f, str.Field(i))
+	for _, field := range structFields(str) {
+		if tagValueExist(field.Tag.Get("goe"), tag) {
+			f = append(f, field)
 		}
 	}
 	return f
@@ -351,6 +398,153 @@ func getTagValue(FieldTag string, subTag string) string {
 	return ""
 }
 
+// getTableName returns the table name set by the "table" tag on the database field
+// mapping typeOf, if there is none returns the name from the driver [model.NamingStrategy]
+func getTableName(tables reflect.Value, typeOf reflect.Type, driver model.Driver) string {
+	if field, ok := tableField(tables, typeOf); ok {
+		if name := getTagValue(field.Tag.Get("goe"), "table:"); name != "" {
+			return name
+		}
+	}
+	return driver.GetDatabaseConfig().GetNamingStrategy().TableName(typeOf.Name())
+}
+
+// tablesOf iterates over the tables of the database struct, including the tables inside schemas
+func tablesOf(tables reflect.Value) iter.Seq[reflect.Value] {
+	return func(yield func(reflect.Value) bool) {
+		for i := range tables.NumField() - 1 {
+			if strings.Contains(tables.Type().Field(i).Tag.Get("goe"), "schema") || strings.HasSuffix(tables.Field(i).Elem().Type().Name(), "Schema") {
+				for s := range tables.Field(i).Elem().NumField() {
+					if !yield(tables.Field(i).Elem().Field(s)) {
+						return
+					}
+				}
+				continue
+			}
+			if !yield(tables.Field(i)) {
+				return
+			}
+		}
+	}
+}
+
+// tableOf returns the pointer to the table of typeOf on the database struct, or a invalid value if typeOf is not a table
+func tableOf(tables reflect.Value, typeOf reflect.Type) reflect.Value {
+	for table := range tablesOf(tables) {
+		if table.Elem().Type() == typeOf {
+			return table
+		}
+	}
+	return reflect.Value{}
+}
+
+// tableField returns the database field mapping typeOf, including the tables inside schemas
+func tableField(tables reflect.Value, typeOf reflect.Type) (reflect.StructField, bool) {
+	var field reflect.StructField
+	for i := range tables.NumField() - 1 {
+		field = tables.Type().Field(i)
+		if strings.Contains(field.Tag.Get("goe"), "schema") || strings.HasSuffix(field.Type.Elem().Name(), "Schema") {
+			for s := range field.Type.Elem().NumField() {
+				if field.Type.Elem().Field(s).Type.Elem() == typeOf {
+					return field.Type.Elem().Field(s), true
+				}
+			}
+		}
+		if field.Type.Elem() == typeOf {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// getColumnName returns the column name set by the "column" tag,
+// if there is none returns the name from the driver [model.NamingStrategy]
+func getColumnName(field reflect.StructField, driver model.Driver) string {
+	if name := getTagValue(field.Tag.Get("goe"), "column:"); name != "" {
+		return name
+	}
+	return driver.GetDatabaseConfig().GetNamingStrategy().ColumnName(field.Name)
+}
+
+// getForeignKey returns the target table and primary key declared by the "fk" tag,
+// if there is none the foreign key is inferred by the field name using [foreignKeyNamePattern]
+func getForeignKey(dbTables reflect.Value, field reflect.StructField) (table, suffix string) {
+	fk := getTagValue(field.Tag.Get("goe"), "fk:")
+	if fk == "" {
+		return foreignKeyNamePattern(dbTables, field.Name)
+	}
+
+	table, suffix, _ = strings.Cut(fk, ".")
+	if suffix == "" && dbTables.FieldByName(table).IsValid() {
+		if pks := getPks(dbTables.FieldByName(table).Elem().Type()); len(pks) != 0 {
+			suffix = pks[0].Name
+		}
+	}
+	return table, suffix
+}
+
+// compositeForeignKey returns the fields of typeOf that declare with the "fk" tag a foreign key to each
+// primary key of the table referenced by field, on the order of the primary keys.
+// Returns nil if the target table has a single primary key or field is not part of the composite foreign key
+func compositeForeignKey(dbTables reflect.Value, typeOf reflect.Type, field reflect.StructField) (fields, targets []reflect.StructField) {
+	if !isForeignKeyTag(field) {
+		return nil, nil
+	}
+	table, _ := getForeignKey(dbTables, field)
+	target := dbTables.FieldByName(table)
+	if !target.IsValid() {
+		return nil, nil
+	}
+	targets = getPks(target.Elem().Type())
+	if len(targets) < 2 {
+		return nil, nil
+	}
+
+	typeFields := structFields(typeOf)
+	fields = make([]reflect.StructField, 0, len(targets))
+	for _, pk := range targets {
+		i := slices.IndexFunc(typeFields, func(f reflect.StructField) bool {
+			if isIgnored(f) || !isForeignKeyTag(f) {
+				return false
+			}
+			t, s := getForeignKey(dbTables, f)
+			return t == table && s == pk.Name
+		})
+		if i == -1 {
+			return nil, nil
+		}
+		fields = append(fields, typeFields[i])
+	}
+
+	if !slices.ContainsFunc(fields, func(f reflect.StructField) bool { return f.Name == field.Name }) {
+		return nil, nil
+	}
+	return fields, targets
+}
+
+// isForeignKeyTag reports whether the foreign key is declared by the "fk" tag
+func isForeignKeyTag(field reflect.StructField) bool {
+	return getTagValue(field.Tag.Get("goe"), "fk:") != ""
+}
+
+// getRelation returns the relation of a foreign key declared by the "fk" tag,
+// by default is many to one and the tag "rel:one" sets a one to one.
+// If the foreign key is not declared the relation is inferred by [isManyToOne]
+func getRelation(b body, createMany func(b body, typeOf reflect.Type) any, createOne func(b body, typeOf reflect.Type) any) any {
+	if !isForeignKeyTag(b.structField) {
+		return isManyToOne(b, createMany, createOne)
+	}
+
+	fieldByName := b.tables.FieldByName(b.tableName)
+	if !fieldByName.IsValid() {
+		return nil
+	}
+	if getTagValue(b.structField.Tag.Get("goe"), "rel:") == "one" {
+		return createOne(b, fieldByName.Elem().Type())
+	}
+	return createMany(b, fieldByName.Elem().Type())
+}
+
 func foreignKeyNamePattern(dbTables reflect.Value, fieldName string) (table, suffix string) {
 	for r := 1; r <= len(fieldName); r++ {
 		table = fieldName[:r]
@@ -371,14 +565,14 @@ func foreignKeyNamePattern(dbTables reflect.Value, fieldName string) (table, suf
 }
 
 func helperAttribute(b body) error {
-	table, prefix := foreignKeyNamePattern(b.tables, b.valueOf.Type().Field(b.fieldId).Name)
+	table, prefix := getForeignKey(b.tables, b.structField)
 	if table != "" {
-		b.stringInfos = stringInfos{prefixName: prefix, tableName: table, fieldName: b.valueOf.Type().Field(b.fieldId).Name}
-		if mto := isManyToOne(b, createManyToOne, createOneToOne); mto != nil {
+		b.stringInfos = stringInfos{prefixName: prefix, tableName: table, fieldName: b.structField.Name}
+		if mto := getRelation(b, createManyToOne, createOneToOne); mto != nil {
 			switch v := mto.(type) {
 			case manyToOne:
-				if addrMap.get(b.mapp.addr) == nil {
-					addrMap.set(b.mapp.addr, v)
+				if b.mapp.db.fields[b.mapp.addr] == nil {
+					b.mapp.db.fields[b.mapp.addr] = v
 				}
 				for i := range b.mapp.pks {
 					if !b.nullable && b.mapp.pks[i].fieldId == v.fieldId {
@@ -386,19 +580,145 @@ func helperAttribute(b body) error {
 					}
 				}
 			case oneToOne:
-				if addrMap.get(b.mapp.addr) == nil {
-					addrMap.set(b.mapp.addr, v)
+				if b.mapp.db.fields[b.mapp.addr] == nil {
+					b.mapp.db.fields[b.mapp.addr] = v
 				}
 			}
 			return nil
 		}
 	}
+	if isForeignKeyTag(b.structField) {
+		// only the foreign keys declared by the tag are invalid, the inferred names that don't match are attributes
+		return fmt.Errorf("goe: struct %q field %q have a invalid foreign key %q", b.typeOf.Name(), b.structField.Name, table+"."+prefix)
+	}
 	newAttr(b)
 	return nil
 }
 
+// getId returns the field named id of typeOf, the fields of the promoted embedded structs
+// are matched as [structFields] and a field of typeOf is preferred over a promoted one
 func getId(typeOf reflect.Type) (reflect.StructField, bool) {
-	return typeOf.FieldByNameFunc(func(s string) bool {
-		return strings.ToUpper(s) == "ID"
-	})
+	var id reflect.StructField
+	ok := false
+	for _, field := range structFields(typeOf) {
+		if strings.ToUpper(field.Name) == "ID" && (!ok || len(field.Index) < len(id.Index)) {
+			id, ok = field, true
+		}
+	}
+	if ok && isIgnored(id) {
+		return reflect.StructField{}, false
+	}
+	return id, ok
+}
+
+// structFields returns the fields of typeOf promoting the fields of embedded structs,
+// the index of each field is the sequence used by [reflect.Value.FieldByIndex]
+func structFields(typeOf reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, typeOf.NumField())
+	for i := range typeOf.NumField() {
+		field := typeOf.Field(i)
+		if isEmbedded(field) {
+			for _, f := range structFields(field.Type) {
+				f.Index = append([]int{i}, f.Index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// fieldsOf iterates over the fields of the struct valueOf promoting the fields of embedded structs,
+// used on values to avoid the allocation of [structFields]
+func fieldsOf(valueOf reflect.Value) iter.Seq2[reflect.StructField, reflect.Value] {
+	return func(yield func(reflect.StructField, reflect.Value) bool) {
+		yieldFields(valueOf, yield)
+	}
+}
+
+// indexedFieldsOf iterates over the fields of the struct valueOf as [fieldsOf],
+// with the index of each field relative to valueOf
+func indexedFieldsOf(valueOf reflect.Value) iter.Seq2[reflect.StructField, reflect.Value] {
+	return func(yield func(reflect.StructField, reflect.Value) bool) {
+		for _, field := range structFields(valueOf.Type()) {
+			if !yield(field, valueOf.FieldByIndex(field.Index)) {
+				return
+			}
+		}
+	}
+}
+
+func yieldFields(valueOf reflect.Value, yield func(reflect.StructField, reflect.Value) bool) bool {
+	for i := range valueOf.NumField() {
+		field := valueOf.Type().Field(i)
+		if isEmbedded(field) {
+			if !yieldFields(valueOf.Field(i), yield) {
+				return false
+			}
+			continue
+		}
+		if !yield(field, valueOf.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmbedded reports whether the field is a embedded struct that have the fields promoted to the table,
+// a exported struct with only exported fields that is not mapped to a column (e.g. time.Time, sql.NullString)
+func isEmbedded(field reflect.StructField) bool {
+	if !field.Anonymous || !field.IsExported() || field.Type.Kind() != reflect.Struct || isIgnored(field) {
+		return false
+	}
+	if field.Type.Implements(valuerType) || reflect.PointerTo(field.Type).Implements(scannerType) {
+		return false
+	}
+	for i := range field.Type.NumField() {
+		if !field.Type.Field(i).IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	valuerType  = reflect.TypeFor[driver.Valuer]()
+	scannerType = reflect.TypeFor[sql.Scanner]()
+)
+
+// isRelation reports whether the field is filled only by Include and not mapped to a column,
+// a slice of structs for has-many or a struct of a table of tables for belongs-to and one to one
+func isRelation(tables reflect.Value, field reflect.StructField) bool {
+	if field.Type.Kind() == reflect.Slice {
+		return field.Type.Elem().Kind() == reflect.Struct
+	}
+	typeOf := derefType(field.Type)
+	if typeOf.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := tableField(tables, typeOf)
+	return ok
+}
+
+// isTableRelation reports whether the field of the struct typeOf is a relation of a table, filled only by Include.
+// The relation fields are never columns of a table, on the other structs every field receives a selected column
+func isTableRelation(tables reflect.Value, typeOf reflect.Type, field reflect.StructField) bool {
+	if _, ok := tableField(tables, typeOf); !ok {
+		return false
+	}
+	return isRelation(tables, field)
+}
+
+// derefType returns the element type if typeOf is a pointer
+func derefType(typeOf reflect.Type) reflect.Type {
+	if typeOf.Kind() == reflect.Pointer {
+		return typeOf.Elem()
+	}
+	return typeOf
+}
+
+// isIgnored reports whether the field is marked with the tag goe:"-" and is not persisted
+func isIgnored(field reflect.StructField) bool {
+	return field.Tag.Get("goe") == "-"
 }
@@ -18,10 +18,10 @@ func handlerValues(ctx context.Context, conn model.Connection, query model.Query
 	return nil
 }
 
-func handlerValuesReturning(ctx context.Context, conn model.Connection, query model.Query, value reflect.Value, pkFieldId int, dbConfig *model.DatabaseConfig) error {
+func handlerValuesReturning(ctx context.Context, conn model.Connection, query model.Query, value reflect.Value, pkFieldIndex []int, dbConfig *model.DatabaseConfig) error {
 	row := wrapperQueryRow(ctx, conn, &query)
 
-	query.Header.Err = row.Scan(value.Field(pkFieldId).Addr().Interface())
+	query.Header.Err = row.Scan(value.FieldByIndex(pkFieldIndex).Addr().Interface())
 	if query.Header.Err != nil {
 		return dbConfig.ErrorQueryHandler(ctx, query)
 	}
@@ -29,7 +29,7 @@ func handlerValuesReturning(ctx context.Context, conn model.Connection, query mo
 	return nil
 }
 
-func handlerValuesReturningBatch(ctx context.Context, conn model.Connection, query model.Query, value reflect.Value, pkFieldId int, dbConfig *model.DatabaseConfig) error {
+func handlerValuesReturningBatch(ctx context.Context, conn model.Connection, query model.Query, value reflect.Value, pkFieldIndex []int, dbConfig *model.DatabaseConfig) error {
 	var rows model.Rows
 	rows, query.Header.Err = wrapperQuery(ctx, conn, &query)
 
@@ -41,7 +41,7 @@ func handlerValuesReturningBatch(ctx context.Context, conn model.Connection, que
 
 	i := 0
 	for rows.Next() {
-		query.Header.Err = rows.Scan(value.Index(i).Field(pkFieldId).Addr().Interface())
+		query.Header.Err = rows.Scan(value.Index(i).FieldByIndex(pkFieldIndex).Addr().Interface())
 		if query.Header.Err != nil {
 			//TODO: add infos about row
 			return dbConfig.ErrorQueryHandler(ctx, query)
@@ -51,11 +51,80 @@ func handlerValuesReturningBatch(ctx context.Context, conn model.Connection, que
 	return nil
 }
 
-func handlerResult[T any](ctx context.Context, conn model.Connection, query model.Query, numFields int, dbConfig *model.DatabaseConfig) iter.Seq2[T, error] {
-	var rows model.Rows
-	rows, query.Header.Err = wrapperQuery(ctx, conn, &query)
+// tableScan scans all the columns of a table into a struct, used when the table can be missing from a row by a left join
+type tableScan struct {
+	typeOf       reflect.Type // struct of the table
+	fieldIndexes [][]int      // field on the struct of each column
+}
+
+// nestedTable is a table selected as a whole by Select, scanned into the struct field of the result with the same type
+type nestedTable struct {
+	column int // first column of the table on the select
+	tableScan
+}
+
+// newTableScan returns the scan of the mapped fields of table with the fields to select
+func newTableScan(addrMap databases, table reflect.Value) (tableScan, []field) {
+	ts := tableScan{typeOf: table.Elem().Type()}
+	fields := make([]field, 0)
+	for structField, fieldOf := range indexedFieldsOf(table.Elem()) {
+		if isIgnored(structField) {
+			continue
+		}
+		if f := addrMap.field(uintptr(fieldOf.Addr().UnsafePointer())); f != nil {
+			fields = append(fields, f)
+			ts.fieldIndexes = append(ts.fieldIndexes, structField.Index)
+		}
+	}
+	return ts, fields
+}
+
+// holders returns the scan destinations of the columns, a null column scans as a nil pointer
+func (ts tableScan) holders() []reflect.Value {
+	holders := make([]reflect.Value, len(ts.fieldIndexes))
+	for i, fieldIndex := range ts.fieldIndexes {
+		holders[i] = reflect.New(reflect.PointerTo(ts.typeOf.FieldByIndex(fieldIndex).Type))
+	}
+	return holders
+}
+
+// set fills target with the scanned holders, if all the holders are null
+// the left join have no match and target is zero (nil if pointer)
+func (ts tableScan) set(target reflect.Value, holders []reflect.Value) {
+	value := reflect.New(ts.typeOf).Elem()
+	matched := false
+	for i, holder := range holders {
+		if holder.Elem().IsNil() {
+			continue
+		}
+		matched = true
+		value.FieldByIndex(ts.fieldIndexes[i]).Set(holder.Elem().Elem())
+	}
+	if !matched {
+		target.SetZero()
+		return
+	}
+	setCompanion(target, value)
+}
 
+// scanTarget is a struct field of the result filled by a tableScan after each row
+type scanTarget struct {
+	value   reflect.Value
+	scan    tableScan
+	holders []reflect.Value
+}
+
+func handlerResult[T any](ctx context.Context, conn model.Connection, query model.Query, numFields int, nested []nestedTable, joins []joinInclude, db *DB) iter.Seq2[T, error] {
+	dbConfig := db.driver.GetDatabaseConfig()
 	var entity T
+	if err := checkQuery(db.driver, &query); err != nil {
+		return func(yield func(T, error) bool) {
+			yield(entity, err)
+		}
+	}
+
+	var rows model.Rows
+	rows, query.Header.Err = wrapperQuery(ctx, conn, &query)
 	if query.Header.Err != nil {
 		return func(yield func(T, error) bool) {
 			yield(entity, dbConfig.ErrorQueryHandler(ctx, query))
@@ -63,10 +132,35 @@ func handlerResult[T any](ctx context.Context, conn model.Connection, query mode
 	}
 	dbConfig.InfoHandler(ctx, query)
 
-	dest := make([]any, numFields)
+	dest := make([]any, 0, numFields)
+	targets := make([]scanTarget, 0, len(nested)+len(joins))
 	value := reflect.ValueOf(&entity).Elem()
-	for i := range dest {
-		dest[i] = value.Field(i).Addr().Interface()
+	for field, fieldOf := range fieldsOf(value) {
+		if len(dest) == numFields {
+			break
+		}
+		if isIgnored(field) {
+			continue
+		}
+		if len(nested) != 0 && nested[0].column == len(dest) && derefType(field.Type) == nested[0].typeOf {
+			targets = append(targets, scanTarget{value: fieldOf, scan: nested[0].tableScan, holders: nested[0].holders()})
+			for _, h := range targets[len(targets)-1].holders {
+				dest = append(dest, h.Interface())
+			}
+			nested = nested[1:]
+			continue
+		}
+		if isTableRelation(db.tables, value.Type(), field) {
+			continue
+		}
+		dest = append(dest, fieldOf.Addr().Interface())
+	}
+
+	for _, ji := range joins {
+		targets = append(targets, scanTarget{value: value.FieldByIndex(ji.relation.fieldIndex), scan: ji.tableScan, holders: ji.holders()})
+		for _, h := range targets[len(targets)-1].holders {
+			dest = append(dest, h.Interface())
+		}
 	}
 
 	return func(yield func(T, error) bool) {
@@ -79,6 +173,9 @@ func handlerResult[T any](ctx context.Context, conn model.Connection, query mode
 				yield(entity, dbConfig.ErrorQueryHandler(ctx, query))
 				return
 			}
+			for _, t := range targets {
+				t.scan.set(t.value, t.holders)
+			}
 			if !yield(entity, nil) {
 				return
 			}
@@ -86,6 +183,45 @@ func handlerResult[T any](ctx context.Context, conn model.Connection, query mode
 	}
 }
 
+// handlerResultSlice returns all the rows as a slice of typeOf, used when the struct type is only known at runtime
+func handlerResultSlice(ctx context.Context, conn model.Connection, query model.Query, numFields int, typeOf reflect.Type, db *DB) (reflect.Value, error) {
+	dbConfig := db.driver.GetDatabaseConfig()
+	if err := checkQuery(db.driver, &query); err != nil {
+		return reflect.Value{}, err
+	}
+
+	var rows model.Rows
+	rows, query.Header.Err = wrapperQuery(ctx, conn, &query)
+	if query.Header.Err != nil {
+		return reflect.Value{}, dbConfig.ErrorQueryHandler(ctx, query)
+	}
+	defer rows.Close()
+	dbConfig.InfoHandler(ctx, query)
+
+	entity := reflect.New(typeOf).Elem()
+	dest := make([]any, 0, numFields)
+	for field, fieldOf := range fieldsOf(entity) {
+		if len(dest) == numFields {
+			break
+		}
+		if isIgnored(field) || isTableRelation(db.tables, typeOf, field) {
+			continue
+		}
+		dest = append(dest, fieldOf.Addr().Interface())
+	}
+
+	result := reflect.MakeSlice(reflect.SliceOf(typeOf), 0, 0)
+	for rows.Next() {
+		query.Header.Err = rows.Scan(dest...)
+		if query.Header.Err != nil {
+			//TODO: add infos about row
+			return reflect.Value{}, dbConfig.ErrorQueryHandler(ctx, query)
+		}
+		result = reflect.Append(result, entity)
+	}
+	return result, nil
+}
+
 func wrapperQuery(ctx context.Context, conn model.Connection, query *model.Query) (model.Rows, error) {
 	queryStart := time.Now()
 	defer func() { query.Header.QueryDuration = time.Since(queryStart) }()
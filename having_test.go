@@ -0,0 +1,46 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/aggregate"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestSelectHaving(t *testing.T) {
+	db, d := openTest(t, false)
+
+	count := aggregate.Count(&db.Animal.Id)
+	_, err := Select[struct {
+		Name  string
+		Count int64
+	}](&db.Habitat.Name, count).
+		Join(&db.Animal.HabitatId, &db.Habitat.Id).
+		Where(where.Equals(&db.Animal.Name, "cat")).
+		GroupBy(&db.Habitat.Name).
+		Having(where.Greater(count, aggregate.Argument(count, 10))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected having, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	if q.Having == nil || q.Having.Attribute.AggregateType != enum.CountAggregate || q.Having.Operator != enum.Greater {
+		t.Fatalf("Expected having count greater, got %+v", q.Having)
+	}
+	if len(q.Arguments) != 2 || q.Arguments[0] != "cat" || q.Arguments[1] != int64(10) {
+		t.Errorf("Expected the where argument before the having argument, got %v", q.Arguments)
+	}
+}
+
+func TestSelectHavingBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	count := aggregate.Count(&db.Animal.Id)
+	_, err := Select[struct{ Name string }](&db.Animal.Name).GroupBy(&db.Animal.Name).
+		Having(where.Greater(count, aggregate.Argument(count, 1))).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,392 @@
+package goe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/internal/operand"
+	"github.com/go-goe/goe/model"
+)
+
+// relation is a field of a table loaded by Include, filled with the rows of the target table
+type relation interface {
+	ownerType() reflect.Type  // struct of the table with the relation field
+	targetType() reflect.Type // struct of the table loaded on the relation field
+	// load fills the relation field of rows, the nested relations are loaded on the targets
+	load(ctx context.Context, conn model.Connection, rows reflect.Value, forUpdate bool, nested []relation) error
+}
+
+// hasMany is a slice field of a table loaded by Include,
+// each child references the parent primary key by a foreign key
+type hasMany struct {
+	fieldIndex []int         // slice field on the parent struct
+	parentType reflect.Type  // struct of the parent table
+	parentKey  []int         // primary key on the parent struct referenced by the children
+	childTable reflect.Value // pointer to the children table on the database struct
+	childKey   []int         // foreign key on the child struct
+}
+
+// belongsTo is a companion field of a many to one or one to one foreign key loaded by Include,
+// filled with the row referenced by the foreign key
+type belongsTo struct {
+	fieldIndex  []int         // companion field on the owner struct, a struct or a pointer to struct
+	ownerTable  reflect.Value // pointer to the table with the foreign key on the database struct
+	foreignKey  []int         // foreign key on the owner struct
+	targetTable reflect.Value // pointer to the referenced table on the database struct
+	targetKey   []int         // primary key on the target struct referenced by the foreign key
+}
+
+// initRelations maps all the slice fields of the tables that are referenced by a foreign key on the children table
+// and all the companion fields of a foreign key of the table, returns a error for each ambiguous has-many relation
+func initRelations(tables reflect.Value, db *DB) (map[uintptr]relation, error) {
+	relations := make(map[uintptr]relation)
+	var errs []error
+	for table := range tablesOf(tables) {
+		parentType := table.Elem().Type()
+		for field, fieldOf := range indexedFieldsOf(table.Elem()) {
+			if isIgnored(field) || !isRelation(tables, field) {
+				continue
+			}
+			if field.Type.Kind() == reflect.Slice {
+				childTable := tableOf(tables, field.Type.Elem())
+				if !childTable.IsValid() {
+					continue
+				}
+				r, ok, err := createHasMany(tables, db, parentType, field, childTable)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if ok {
+					r.fieldIndex = field.Index
+					relations[uintptr(fieldOf.Addr().UnsafePointer())] = r
+				}
+				continue
+			}
+			targetTable := tableOf(tables, derefType(field.Type))
+			if !targetTable.IsValid() {
+				continue
+			}
+			if r, ok := createBelongsTo(tables, db, table, field, targetTable); ok {
+				relations[uintptr(fieldOf.Addr().UnsafePointer())] = r
+			}
+		}
+	}
+	return relations, errors.Join(errs...)
+}
+
+// createHasMany returns the relation if the children table have a foreign key to the parent table,
+// if more than one foreign key references the parent the slice field needs to name one with the tag "fk"
+func createHasMany(tables reflect.Value, db *DB, parentType reflect.Type, slice reflect.StructField, childTable reflect.Value) (hasMany, bool, error) {
+	fk := getTagValue(slice.Tag.Get("goe"), "fk:")
+	var matches []hasMany
+	var names []string
+	for field, fieldOf := range indexedFieldsOf(childTable.Elem()) {
+		if isIgnored(field) || (fk != "" && field.Name != fk) {
+			continue
+		}
+		switch f := db.fields[uintptr(fieldOf.Addr().UnsafePointer())]; f.(type) {
+		case manyToOne, oneToOne:
+			// the children of a composite foreign key are not matched by a single key
+			if m, ok := f.(manyToOne); ok && len(m.composite) != 0 {
+				continue
+			}
+			table, prefix := getForeignKey(tables, field)
+			if target := tables.FieldByName(table); !target.IsValid() || target.Elem().Type() != parentType {
+				continue
+			}
+			parentKey, ok := parentType.FieldByName(prefix)
+			if !ok {
+				continue
+			}
+			matches = append(matches, hasMany{
+				parentType: parentType,
+				parentKey:  parentKey.Index,
+				childTable: childTable,
+				childKey:   field.Index,
+			})
+			names = append(names, childTable.Elem().Type().Name()+"."+field.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return hasMany{}, false, nil
+	case 1:
+		return matches[0], true, nil
+	}
+	return hasMany{}, false, fmt.Errorf("goe: struct %q field %q have a ambiguous relation, matches %q. try naming the foreign key of the children with the tag \"fk\", e.g. goe:\"fk:%v\"", parentType.Name(), slice.Name, names, childTable.Elem().Type().FieldByIndex(matches[0].childKey).Name)
+}
+
+// createBelongsTo returns the relation if the owner table have a foreign key to the target table,
+// if more than one foreign key references the target the one named with the companion field as prefix is used
+func createBelongsTo(tables reflect.Value, db *DB, ownerTable reflect.Value, companion reflect.StructField, targetTable reflect.Value) (belongsTo, bool) {
+	var r belongsTo
+	found := false
+	for field, fieldOf := range indexedFieldsOf(ownerTable.Elem()) {
+		if isIgnored(field) {
+			continue
+		}
+		switch f := db.fields[uintptr(fieldOf.Addr().UnsafePointer())]; f.(type) {
+		case manyToOne, oneToOne:
+			if m, ok := f.(manyToOne); ok && len(m.composite) != 0 {
+				continue
+			}
+			table, prefix := getForeignKey(tables, field)
+			if target := tables.FieldByName(table); !target.IsValid() || target.Elem().Type() != targetTable.Elem().Type() {
+				continue
+			}
+			targetKey, ok := targetTable.Elem().Type().FieldByName(prefix)
+			if !ok {
+				continue
+			}
+			fk := belongsTo{
+				fieldIndex:  companion.Index,
+				ownerTable:  ownerTable,
+				foreignKey:  field.Index,
+				targetTable: targetTable,
+				targetKey:   targetKey.Index,
+			}
+			if strings.HasPrefix(field.Name, companion.Name) {
+				return fk, true
+			}
+			if !found {
+				r, found = fk, true
+			}
+		}
+	}
+	return r, found
+}
+
+// getArgRelation returns the relation of the field pointed by arg
+func getArgRelation(db *DB, arg any) relation {
+	valueOf := reflect.ValueOf(arg)
+	if valueOf.Kind() != reflect.Pointer {
+		panic("goe: invalid include. try sending a pointer to a relation field of a database mapped struct as argument")
+	}
+	r, ok := db.relations[uintptr(valueOf.UnsafePointer())]
+	if !ok {
+		panic("goe: invalid include. try sending a pointer to a relation field of a database mapped struct as argument")
+	}
+	return r
+}
+
+// getArgsInclude returns the relations of the fields, the owner of each relation
+// needs to be the result struct or the target of a previous relation
+func getArgsInclude(db *DB, typeOf reflect.Type, includes []relation, args ...any) []relation {
+	for _, arg := range args {
+		r := getArgRelation(db, arg)
+		if r.ownerType() != typeOf && !slices.ContainsFunc(includes, func(i relation) bool {
+			return i.targetType() == r.ownerType()
+		}) {
+			panic("goe: invalid include. try including the parent relation first")
+		}
+		includes = append(includes, r)
+	}
+	return includes
+}
+
+// loadIncludes fills the relation fields of rows with the relations of the rows type,
+// the relations after each one are loaded on the targets
+func loadIncludes(ctx context.Context, conn model.Connection, rows reflect.Value, forUpdate bool, includes []relation) error {
+	for i, r := range includes {
+		if r.ownerType() != rows.Type().Elem() {
+			continue
+		}
+		if err := r.load(ctx, conn, rows, forUpdate, includes[i+1:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r hasMany) ownerType() reflect.Type {
+	return r.parentType
+}
+
+func (r hasMany) targetType() reflect.Type {
+	return r.childTable.Elem().Type()
+}
+
+func (r hasMany) load(ctx context.Context, conn model.Connection, rows reflect.Value, forUpdate bool, nested []relation) error {
+	keys := collectKeys(rows, r.parentKey)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	children, err := loadTable(ctx, conn, r.childTable, r.childKey, keys, forUpdate)
+	if err != nil {
+		return err
+	}
+	if err = loadIncludes(ctx, conn, children, forUpdate, nested); err != nil {
+		return err
+	}
+
+	byKey := make(map[any][]int)
+	for c := range children.Len() {
+		if key, ok := keyOf(children.Index(c).FieldByIndex(r.childKey)); ok {
+			byKey[key] = append(byKey[key], c)
+		}
+	}
+
+	for p := range rows.Len() {
+		key, _ := keyOf(rows.Index(p).FieldByIndex(r.parentKey))
+		slice := reflect.MakeSlice(children.Type(), 0, len(byKey[key]))
+		for _, c := range byKey[key] {
+			slice = reflect.Append(slice, children.Index(c))
+		}
+		rows.Index(p).FieldByIndex(r.fieldIndex).Set(slice)
+	}
+	return nil
+}
+
+func (r belongsTo) ownerType() reflect.Type {
+	return r.ownerTable.Elem().Type()
+}
+
+func (r belongsTo) targetType() reflect.Type {
+	return r.targetTable.Elem().Type()
+}
+
+func (r belongsTo) load(ctx context.Context, conn model.Connection, rows reflect.Value, forUpdate bool, nested []relation) error {
+	keys := collectKeys(rows, r.foreignKey)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	targets, err := loadTable(ctx, conn, r.targetTable, r.targetKey, keys, forUpdate)
+	if err != nil {
+		return err
+	}
+	if err = loadIncludes(ctx, conn, targets, forUpdate, nested); err != nil {
+		return err
+	}
+
+	byKey := make(map[any]int, targets.Len())
+	for t := range targets.Len() {
+		if key, ok := keyOf(targets.Index(t).FieldByIndex(r.targetKey)); ok {
+			byKey[key] = t
+		}
+	}
+
+	for p := range rows.Len() {
+		key, ok := keyOf(rows.Index(p).FieldByIndex(r.foreignKey))
+		if !ok {
+			continue
+		}
+		if t, ok := byKey[key]; ok {
+			setCompanion(rows.Index(p).FieldByIndex(r.fieldIndex), targets.Index(t))
+		}
+	}
+	return nil
+}
+
+// joinInclude is a belongs-to relation loaded by IncludeJoin, the target columns
+// are selected with a left join after the columns of the owner
+type joinInclude struct {
+	relation belongsTo
+	tableScan
+}
+
+// getArgJoinInclude returns the relation of arg with the join fields and the target fields to select,
+// the relation needs to be a belongs-to of typeOf referencing other table
+func getArgJoinInclude(db *DB, typeOf reflect.Type, arg any) (joinInclude, []field, []field) {
+	r, ok := getArgRelation(db, arg).(belongsTo)
+	if !ok || r.ownerType() != typeOf {
+		panic("goe: invalid include join. try sending a pointer to a companion field of a foreign key on the selected struct as argument")
+	}
+	if r.targetType() == typeOf {
+		panic("goe: invalid include join. try using Include for a self-referencing relation")
+	}
+
+	addrMap := loadFields()
+	join := []field{
+		addrMap.field(uintptr(r.ownerTable.Elem().FieldByIndex(r.foreignKey).Addr().UnsafePointer())),
+		addrMap.field(uintptr(r.targetTable.Elem().FieldByIndex(r.targetKey).Addr().UnsafePointer())),
+	}
+
+	ts, fields := newTableScan(addrMap, r.targetTable)
+	return joinInclude{relation: r, tableScan: ts}, join, fields
+}
+
+// setCompanion sets target on the companion field, a pointer companion receives a copy of target
+func setCompanion(companion, target reflect.Value) {
+	if companion.Kind() == reflect.Pointer {
+		ptr := reflect.New(target.Type())
+		ptr.Elem().Set(target)
+		companion.Set(ptr)
+		return
+	}
+	companion.Set(target)
+}
+
+// collectKeys returns the distinct non nil values of the field on rows
+func collectKeys(rows reflect.Value, fieldIndex []int) []any {
+	keys := make([]any, 0, rows.Len())
+	seen := make(map[any]bool, rows.Len())
+	for p := range rows.Len() {
+		fieldOf := rows.Index(p).FieldByIndex(fieldIndex)
+		if key, ok := keyOf(fieldOf); ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, fieldOf.Interface())
+		}
+	}
+	return keys
+}
+
+// maxIncludeKeys is the number of keys on each query of loadTable, under the query arguments limit of the databases
+const maxIncludeKeys = 1000
+
+// loadTable returns all the rows of table with the field on fieldIndex in keys,
+// the keys are split in queries of maxIncludeKeys
+func loadTable(ctx context.Context, conn model.Connection, table reflect.Value, fieldIndex []int, keys []any, forUpdate bool) (reflect.Value, error) {
+	rows := reflect.MakeSlice(reflect.SliceOf(table.Elem().Type()), 0, len(keys))
+	for chunk := range slices.Chunk(keys, maxIncludeKeys) {
+		chunkRows, err := loadTableKeys(ctx, conn, table, fieldIndex, chunk, forUpdate)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rows = reflect.AppendSlice(rows, chunkRows)
+	}
+	return rows, nil
+}
+
+// loadTableKeys returns the rows of table with the field on fieldIndex in keys, on a single query
+func loadTableKeys(ctx context.Context, conn model.Connection, table reflect.Value, fieldIndex []int, keys []any, forUpdate bool) (reflect.Value, error) {
+	args := getArgsList(loadFields(), table.Interface())
+
+	b := createBuilder(enum.SelectQuery)
+	b.fieldsSelect = args.fields
+	b.buildSelect()
+	b.query.ForUpdate = forUpdate
+
+	w := model.Where{
+		Arg:      table.Elem().FieldByIndex(fieldIndex).Addr().Interface(),
+		Value:    operand.Value{Value: keys},
+		Operator: enum.In,
+		Type:     enum.OperationInWhere,
+	}
+	helperWhere(&b, loadFields(), &w)
+	b.query.Where = &w
+	b.buildSqlSelect()
+
+	return handlerResultSlice(ctx, conn, b.query, len(b.fieldsSelect), table.Elem().Type(), b.fieldsSelect[0].getDb())
+}
+
+// keyOf returns the comparable value of a key used to match parents and children, a nil pointer is not a key
+func keyOf(valueOf reflect.Value) (any, bool) {
+	if valueOf.Kind() == reflect.Pointer {
+		if valueOf.IsNil() {
+			return nil, false
+		}
+		valueOf = valueOf.Elem()
+	}
+	if valueOf.Kind() == reflect.Slice {
+		return string(valueOf.Bytes()), true
+	}
+	return valueOf.Interface(), true
+}
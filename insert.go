@@ -75,9 +75,9 @@ func (s stateInsert[T]) One(value *T) error {
 	}
 	valueOf := reflect.ValueOf(value).Elem()
 
-	s.builder.fields = getArgsTable(addrMap.mapField, s.table, valueOf)
+	s.builder.fields = getArgsTable(loadFields(), s.table, valueOf)
 
-	pkFieldId := s.builder.buildSqlInsert(valueOf)
+	pkFieldIndex := s.builder.buildSqlInsert(valueOf)
 
 	driver := s.builder.fields[0].getDb().driver
 	if s.conn == nil {
@@ -85,7 +85,7 @@ func (s stateInsert[T]) One(value *T) error {
 	}
 
 	if s.builder.query.ReturningID != nil {
-		return handlerValuesReturning(s.ctx, s.conn, s.builder.query, valueOf, pkFieldId, driver.GetDatabaseConfig())
+		return handlerValuesReturning(s.ctx, s.conn, s.builder.query, valueOf, pkFieldIndex, driver.GetDatabaseConfig())
 	}
 	return handlerValues(s.ctx, s.conn, s.builder.query, driver.GetDatabaseConfig())
 }
@@ -96,23 +96,23 @@ func (s stateInsert[T]) All(value []T) error {
 	}
 	valueOf := reflect.ValueOf(value)
 
-	s.builder.fields = getArgsTable(addrMap.mapField, s.table, valueOf)
+	s.builder.fields = getArgsTable(loadFields(), s.table, valueOf)
 
-	pkFieldId := s.builder.buildSqlInsertBatch(valueOf)
+	pkFieldIndex := s.builder.buildSqlInsertBatch(valueOf)
 
 	driver := s.builder.fields[0].getDb().driver
 	if s.conn == nil {
 		s.conn = driver.NewConnection()
 	}
 
-	return handlerValuesReturningBatch(s.ctx, s.conn, s.builder.query, valueOf, pkFieldId, driver.GetDatabaseConfig())
+	return handlerValuesReturningBatch(s.ctx, s.conn, s.builder.query, valueOf, pkFieldIndex, driver.GetDatabaseConfig())
 }
 
 func createInsertState[T any](ctx context.Context, t *T) stateInsert[T] {
 	return stateInsert[T]{builder: createBuilder(enum.InsertQuery), ctx: ctx, table: t}
 }
 
-func getArgsTable(addrMap map[uintptr]field, table any, valueOf reflect.Value) []field {
+func getArgsTable(addrMap databases, table any, valueOf reflect.Value) []field {
 	if table == nil {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
@@ -123,15 +123,13 @@ func getArgsTable(addrMap map[uintptr]field, table any, valueOf reflect.Value) [
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
 
-	var fieldOf reflect.Value
-	for i := 0; i < tableValueOf.NumField(); i++ {
-		fieldOf = tableValueOf.Field(i)
-		if fieldOf.Kind() == reflect.Slice && fieldOf.Type().Elem().Kind() == reflect.Struct {
+	for structField, fieldOf := range fieldsOf(tableValueOf) {
+		if isIgnored(structField) {
 			continue
 		}
-		field := addrMap[uintptr(fieldOf.Addr().UnsafePointer())]
+		field := addrMap.field(uintptr(fieldOf.Addr().UnsafePointer()))
 		if field != nil {
-			if field.getDefault() && valueOf.Field(field.getFieldId()).IsZero() {
+			if field.getDefault() && valueOf.FieldByIndex(field.getFieldIndex()).IsZero() {
 				continue
 			}
 			fields = append(fields, field)
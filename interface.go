@@ -9,7 +9,7 @@ type field interface {
 	fieldDb
 	isPrimaryKey() bool
 	getTableId() int
-	getFieldId() int
+	getFieldIndex() []int
 	getDefault() bool
 	getAttributeName() string
 	buildAttributeInsert(*builder)
@@ -25,4 +25,5 @@ type fieldSelect interface {
 	table() string
 	schema() *string
 	getTableId() int
+	getTable() model.Table
 }
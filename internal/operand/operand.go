@@ -0,0 +1,17 @@
+// Package operand has the value operands of the where operations used by goe and the where package
+package operand
+
+import "github.com/go-goe/goe/model"
+
+// Value is the value of a where operation, a value that is a [model.ValueOperation]
+// (e.g. function.Argument) is unwrapped by GetValue
+type Value struct {
+	Value any
+}
+
+func (v Value) GetValue() any {
+	if result, ok := v.Value.(model.ValueOperation); ok {
+		return result.GetValue()
+	}
+	return v.Value
+}
@@ -0,0 +1,66 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestJoinOn(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Select[struct{ Animal, Habitat string }](&db.Animal.Name, &db.Habitat.Name).
+		LeftJoinOn(db.Habitat, where.And(where.EqualsArg[int](&db.Habitat.Id, &db.Animal.HabitatId), where.NotEquals(&db.Habitat.Name, "desert"))).
+		Where(where.Equals(&db.Animal.Name, "cat")).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected join on, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	if len(q.Joins) != 1 || q.Joins[0].On == nil || q.Joins[0].JoinOperation != enum.LeftJoin {
+		t.Fatalf("Expected a left join on a condition, got %+v", q.Joins)
+	}
+	if q.Joins[0].OnIndex != 1 || q.WhereIndex != 2 {
+		t.Errorf("Expected the join argument before the where argument, got %v and %v", q.Joins[0].OnIndex, q.WhereIndex)
+	}
+	if len(q.Arguments) != 2 || q.Arguments[0] != "desert" || q.Arguments[1] != "cat" {
+		t.Errorf("Expected the join argument before the where argument, got %v", q.Arguments)
+	}
+
+	_, err = Select[struct{ Animal, Habitat string }](&db.Animal.Name, &db.Habitat.Name).CrossJoin(db.Habitat).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected cross join, got error %v", err)
+	}
+	if q = d.lastQuery(t); len(q.Joins) != 1 || q.Joins[0].JoinOperation != enum.CrossJoin || q.Joins[0].On != nil {
+		t.Errorf("Expected a cross join, got %+v", q.Joins)
+	}
+}
+
+func TestJoinBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	for _, j := range []struct {
+		desc string
+		join func() error
+	}{
+		{desc: "join on", join: func() error {
+			_, err := Select[struct{ Animal, Habitat string }](&db.Animal.Name, &db.Habitat.Name).
+				JoinOn(db.Habitat, where.EqualsArg[int](&db.Habitat.Id, &db.Animal.HabitatId)).AsSlice()
+			return err
+		}},
+		{desc: "full join", join: func() error {
+			_, err := Select[struct{ Animal, Habitat string }](&db.Animal.Name, &db.Habitat.Name).
+				FullJoin(&db.Animal.HabitatId, &db.Habitat.Id).AsSlice()
+			return err
+		}},
+		{desc: "cross join", join: func() error {
+			_, err := Select[struct{ Animal, Habitat string }](&db.Animal.Name, &db.Habitat.Name).CrossJoin(db.Habitat).AsSlice()
+			return err
+		}},
+	} {
+		if err := j.join(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected ErrUnsupported on %v, got %v", j.desc, err)
+		}
+	}
+}
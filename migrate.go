@@ -3,6 +3,7 @@ package goe
 import (
 	"context"
 
+	"github.com/go-goe/goe/model"
 	"github.com/go-goe/goe/utils"
 )
 
@@ -53,27 +54,76 @@ func (m migrate) AutoMigrateContext(ctx context.Context) error {
 func (mt migrateTable) DropTable() error {
 	return mt.db.driver.DropTable(
 		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(mt.schema)),
-		mt.db.driver.KeywordHandler(utils.TableNamePattern(mt.table)))
+		mt.tableName(mt.table))
 }
 
 func (mt migrateTable) RenameTable(newName string) error {
 	return mt.db.driver.RenameTable(
 		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(mt.schema)),
-		mt.db.driver.KeywordHandler(utils.TableNamePattern(mt.table)),
-		mt.db.driver.KeywordHandler(utils.TableNamePattern(newName)))
+		mt.tableName(mt.table),
+		mt.tableName(newName))
 }
 
 func (mt migrateTable) DropColumn(column string) error {
 	return mt.db.driver.DropColumn(
 		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(mt.schema)),
-		mt.db.driver.KeywordHandler(utils.TableNamePattern(mt.table)),
-		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(column)))
+		mt.tableName(mt.table),
+		mt.columnName(column))
 }
 
 func (mt migrateTable) RenameColumn(column, newName string) error {
 	return mt.db.driver.RenameColumn(
 		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(mt.schema)),
-		mt.db.driver.KeywordHandler(utils.TableNamePattern(mt.table)),
-		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(column)),
-		mt.db.driver.KeywordHandler(utils.ColumnNamePattern(newName)))
+		mt.tableName(mt.table),
+		mt.columnName(column),
+		mt.columnName(newName))
+}
+
+// mappedTable returns the table mapped by the struct name on the schema of mt
+func (mt migrateTable) mappedTable(structName string) *model.TableMigrate {
+	schema := mt.db.driver.KeywordHandler(utils.ColumnNamePattern(mt.schema))
+	for _, t := range mt.db.migrator.Tables {
+		if t.StructName == structName && (mt.schema == "" || t.Schema != nil && *t.Schema == schema) {
+			return t
+		}
+	}
+	return nil
+}
+
+// tableName returns the name of the table mapped by the struct name, with the table tag and the naming strategy.
+// The names of structs not mapped by the database (e.g. the new name of a renamed table) use the naming strategy
+func (mt migrateTable) tableName(structName string) string {
+	if t := mt.mappedTable(structName); t != nil {
+		return t.EscapingName
+	}
+	return mt.db.driver.KeywordHandler(mt.db.driver.GetDatabaseConfig().GetNamingStrategy().TableName(structName))
+}
+
+// columnName returns the name of the column mapped by the field name on the table of mt, with the column tag and the naming strategy.
+// The names of fields not mapped by the table (e.g. the new name of a renamed column) use the naming strategy
+func (mt migrateTable) columnName(fieldName string) string {
+	if t := mt.mappedTable(mt.table); t != nil {
+		for _, a := range tableAttributes(t) {
+			if a.FieldName == fieldName {
+				return a.EscapingName
+			}
+		}
+	}
+	return mt.db.driver.KeywordHandler(mt.db.driver.GetDatabaseConfig().GetNamingStrategy().ColumnName(fieldName))
+}
+
+// tableAttributes returns all the columns of t, the primary keys, attributes and foreign keys
+func tableAttributes(t *model.TableMigrate) []model.AttributeMigrate {
+	attributes := make([]model.AttributeMigrate, 0, len(t.PrimaryKeys)+len(t.Attributes)+len(t.ManyToOnes)+len(t.OneToOnes))
+	for _, pk := range t.PrimaryKeys {
+		attributes = append(attributes, pk.AttributeMigrate)
+	}
+	attributes = append(attributes, t.Attributes...)
+	for _, mto := range t.ManyToOnes {
+		attributes = append(attributes, mto.AttributeMigrate)
+	}
+	for _, oto := range t.OneToOnes {
+		attributes = append(attributes, oto.AttributeMigrate)
+	}
+	return attributes
 }
@@ -1,11 +1,14 @@
 package goe
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
 	"strings"
 
+	"github.com/go-goe/goe/enum"
 	"github.com/go-goe/goe/model"
 	"github.com/go-goe/goe/utils"
 )
@@ -25,52 +28,143 @@ func migrateFrom(db any, driver model.Driver) *model.Migrator {
 
 	migrator := new(model.Migrator)
 	migrator.Tables = make(map[string]*model.TableMigrate)
+	var errs []error
 	for i := range valueOf.NumField() - 1 {
 		if strings.Contains(valueOf.Type().Field(i).Tag.Get("goe"), "schema") || strings.HasSuffix(valueOf.Field(i).Elem().Type().Name(), "Schema") {
 			schema := driver.KeywordHandler(utils.ColumnNamePattern(valueOf.Field(i).Elem().Type().Name()))
 			migrator.Schemas = append(migrator.Schemas, schema)
 			for f := range valueOf.Field(i).Elem().NumField() {
-				migrator.Error = typeField(valueOf, valueOf.Field(i).Elem().Field(f), migrator, driver, &schema, schemasMap)
-				if migrator.Error != nil {
-					return migrator
-				}
+				errs = append(errs, typeField(valueOf, valueOf.Field(i).Elem().Field(f), migrator, driver, &schema, schemasMap))
 			}
 			continue
 		}
 
-		migrator.Error = typeField(valueOf, valueOf.Field(i), migrator, driver, nil, schemasMap)
-		if migrator.Error != nil {
-			return migrator
-		}
+		errs = append(errs, typeField(valueOf, valueOf.Field(i), migrator, driver, nil, schemasMap))
 	}
 
+	migrator.Error = errors.Join(errs...)
 	return migrator
 }
 
+// newMetadata returns the metadata of the migrator tables ordered by schema and name,
+// the slices are new on each call so the metadata can be changed by the caller
+func newMetadata(tables reflect.Value, migrator *model.Migrator) model.Metadata {
+	structs := make(map[string]reflect.Type)
+	for table := range tablesOf(tables) {
+		structs[table.Elem().Type().Name()] = table.Elem().Type()
+	}
+
+	metadata := model.Metadata{Schemas: slices.Clone(migrator.Schemas)}
+	for _, t := range migrator.Tables {
+		table := model.TableMetadata{Name: t.Name, StructName: t.StructName, Schema: t.Schema}
+		addColumn := func(at model.AttributeMigrate, primaryKey, autoIncrement bool) {
+			if slices.ContainsFunc(table.Columns, func(c model.ColumnMetadata) bool { return c.Name == at.Name }) {
+				return
+			}
+			// the field can be promoted by a embedded struct
+			field, _ := structs[t.StructName].FieldByName(at.FieldName)
+			table.Columns = append(table.Columns, model.ColumnMetadata{
+				Name:          at.Name,
+				FieldName:     at.FieldName,
+				GoType:        field.Type,
+				DataType:      at.DataType,
+				Nullable:      at.Nullable,
+				Default:       at.Default,
+				PrimaryKey:    primaryKey,
+				AutoIncrement: autoIncrement,
+			})
+		}
+
+		for _, pk := range t.PrimaryKeys {
+			addColumn(pk.AttributeMigrate, true, pk.AutoIncrement)
+		}
+		for _, at := range t.Attributes {
+			addColumn(at, false, false)
+		}
+		for _, mto := range t.ManyToOnes {
+			addColumn(mto.AttributeMigrate, false, false)
+			relation := model.RelationMetadata{
+				Type:           enum.ManyToOneRelation,
+				ForeignKeyName: mto.ForeignKeyName,
+				Columns:        []string{mto.Name},
+				TargetSchema:   mto.TargetSchema,
+				TargetTable:    mto.TargetTable,
+				TargetColumns:  []string{mto.TargetColumn},
+			}
+			for _, c := range mto.Composite {
+				relation.Columns = append(relation.Columns, c.Name)
+				relation.TargetColumns = append(relation.TargetColumns, c.TargetColumn)
+			}
+			table.Relations = append(table.Relations, relation)
+		}
+		for _, oto := range t.OneToOnes {
+			addColumn(oto.AttributeMigrate, false, false)
+			table.Relations = append(table.Relations, model.RelationMetadata{
+				Type:           enum.OneToOneRelation,
+				ForeignKeyName: oto.ForeignKeyName,
+				Columns:        []string{oto.Name},
+				TargetSchema:   oto.TargetSchema,
+				TargetTable:    oto.TargetTable,
+				TargetColumns:  []string{oto.TargetColumn},
+			})
+		}
+		for i, c := range table.Columns {
+			// a primary key that is also a foreign key is not auto increment
+			if slices.ContainsFunc(table.Relations, func(r model.RelationMetadata) bool { return slices.Contains(r.Columns, c.Name) }) {
+				table.Columns[i].AutoIncrement = false
+			}
+		}
+		for _, in := range t.Indexes {
+			index := model.IndexMetadata{Name: in.Name, Unique: in.Unique, Func: in.Func}
+			for _, at := range in.Attributes {
+				index.Columns = append(index.Columns, at.Name)
+			}
+			table.Indexes = append(table.Indexes, index)
+		}
+		metadata.Tables = append(metadata.Tables, table)
+	}
+
+	slices.SortFunc(metadata.Tables, func(a, b model.TableMetadata) int {
+		var schemaA, schemaB string
+		if a.Schema != nil {
+			schemaA = *a.Schema
+		}
+		if b.Schema != nil {
+			schemaB = *b.Schema
+		}
+		return cmp.Or(strings.Compare(schemaA, schemaB), strings.Compare(a.Name, b.Name))
+	})
+	return metadata
+}
+
 func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migrator, driver model.Driver, schema *string, schemasMap map[string]*string) error {
 	valueOf = valueOf.Elem()
 	pks, fieldNames, err := migratePk(valueOf.Type(), driver)
 	if err != nil {
-		return err
+		// the struct without primary key is returned by the mapping on Open
+		return nil
 	}
 	table := new(model.TableMigrate)
 
-	table.Name = utils.TableNamePattern(valueOf.Type().Name())
+	table.Name = getTableName(tables, valueOf.Type(), driver)
+	table.StructName = valueOf.Type().Name()
 	table.Schema = schema
-	var field reflect.StructField
+	var fieldOf reflect.Value
+	var errs []error
 
-	for fieldId := range valueOf.NumField() {
-		field = valueOf.Type().Field(fieldId)
-		if skipPrimaryKey(fieldNames, field.Name, tables, field) {
+	for fieldId, field := range structFields(valueOf.Type()) {
+		if isIgnored(field) || isRelation(tables, field) || skipPrimaryKey(fieldNames, field.Name, tables, field) {
 			continue
 		}
-		switch valueOf.Field(fieldId).Kind() {
+		fieldOf = valueOf.FieldByIndex(field.Index)
+		switch fieldOf.Kind() {
 		case reflect.Slice:
 			err = handlerSlice(body{
 				fieldId:     fieldId,
+				structField: field,
 				driver:      driver,
 				tables:      tables,
-				fieldTypeOf: valueOf.Field(fieldId).Type().Elem(),
+				fieldTypeOf: fieldOf.Type().Elem(),
 				typeOf:      valueOf.Type(),
 				valueOf:     valueOf,
 				migrate: &infosMigrate{
@@ -80,15 +174,14 @@ func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migr
 				},
 				schemasMap: schemasMap,
 			}, helperAttributeMigrate)
-			if err != nil {
-				return err
-			}
+			errs = append(errs, err)
 		case reflect.Struct:
 			err = handlerStruct(body{
 				fieldId:     fieldId,
+				structField: field,
 				driver:      driver,
 				nullable:    isNullable(field),
-				fieldTypeOf: valueOf.Field(fieldId).Type(),
+				fieldTypeOf: fieldOf.Type(),
 				valueOf:     valueOf,
 				migrate: &infosMigrate{
 					table: table,
@@ -96,17 +189,16 @@ func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migr
 				},
 				schemasMap: schemasMap,
 			}, migrateAtt)
-			if err != nil {
-				return err
-			}
+			errs = append(errs, err)
 		case reflect.Pointer:
 			err = helperAttributeMigrate(body{
-				fieldId:  fieldId,
-				driver:   driver,
-				nullable: true,
-				tables:   tables,
-				valueOf:  valueOf,
-				typeOf:   valueOf.Type(),
+				fieldId:     fieldId,
+				structField: field,
+				driver:      driver,
+				nullable:    true,
+				tables:      tables,
+				valueOf:     valueOf,
+				typeOf:      valueOf.Type(),
 				migrate: &infosMigrate{
 					table:      table,
 					field:      field,
@@ -114,16 +206,15 @@ func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migr
 				},
 				schemasMap: schemasMap,
 			})
-			if err != nil {
-				return err
-			}
+			errs = append(errs, err)
 		default:
 			err = helperAttributeMigrate(body{
-				fieldId: fieldId,
-				driver:  driver,
-				tables:  tables,
-				valueOf: valueOf,
-				typeOf:  valueOf.Type(),
+				fieldId:     fieldId,
+				structField: field,
+				driver:      driver,
+				tables:      tables,
+				valueOf:     valueOf,
+				typeOf:      valueOf.Type(),
 				migrate: &infosMigrate{
 					table:      table,
 					field:      field,
@@ -131,9 +222,7 @@ func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migr
 				},
 				schemasMap: schemasMap,
 			})
-			if err != nil {
-				return err
-			}
+			errs = append(errs, err)
 		}
 	}
 
@@ -143,7 +232,7 @@ func typeField(tables reflect.Value, valueOf reflect.Value, migrator *model.Migr
 
 	table.EscapingName = driver.KeywordHandler(table.Name)
 	migrator.Tables[table.Name] = table
-	return nil
+	return errors.Join(errs...)
 }
 
 func createManyToOneMigrate(b body, typeOf reflect.Type) any {
@@ -155,24 +244,40 @@ func createManyToOneMigrate(b body, typeOf reflect.Type) any {
 		}
 	}
 
-	if count == 0 {
+	fields, targets := compositeForeignKey(b.tables, b.typeOf, b.migrate.field)
+	if count == 0 && fields == nil {
 		return nil
 	}
 
+	if fields != nil && fields[0].Name != b.migrate.field.Name {
+		// the composite foreign key is created by the first column, the others are attributes
+		return (*model.ManyToOneMigrate)(nil)
+	}
+
 	mto := new(model.ManyToOneMigrate)
+	for i := 1; i < len(fields); i++ {
+		c := model.CompositeMigrate{Name: getColumnName(fields[i], b.driver), TargetColumn: getColumnName(targets[i], b.driver)}
+		c.EscapingName = b.driver.KeywordHandler(c.Name)
+		c.EscapingTargetColumn = b.driver.KeywordHandler(c.TargetColumn)
+		mto.Composite = append(mto.Composite, c)
+	}
 
-	mto.TargetTable = utils.TableNamePattern(typeOf.Name())
-	mto.TargetColumn = utils.ColumnNamePattern(b.prefixName)
+	targetPk, _ := typeOf.FieldByName(b.prefixName)
+	mto.TargetTable = getTableName(b.tables, typeOf, b.driver)
+	mto.TargetColumn = getColumnName(targetPk, b.driver)
 	mto.TargetSchema = b.schemasMap[typeOf.Name()]
 	mto.EscapingTargetTable = b.driver.KeywordHandler(mto.TargetTable)
 	mto.EscapingTargetColumn = b.driver.KeywordHandler(mto.TargetColumn)
 
-	mto.Name = utils.ColumnNamePattern(b.fieldName)
+	mto.Name = getColumnName(b.migrate.field, b.driver)
 	mto.EscapingName = b.driver.KeywordHandler(mto.Name)
+	mto.FieldName = b.migrate.field.Name
+	mto.ForeignKeyName = b.driver.GetDatabaseConfig().GetNamingStrategy().ForeignKeyName(b.migrate.table.Name, mto.Name)
+	mto.EscapingForeignKeyName = b.driver.KeywordHandler(mto.ForeignKeyName)
 	mto.Nullable = b.nullable
 	mto.Default = getTagValue(b.migrate.field.Tag.Get("goe"), "default:")
 	if err := checkIndex(b, mto.AttributeMigrate, true); err != nil {
-		panic(err)
+		return err
 	}
 	return mto
 }
@@ -192,17 +297,21 @@ func createOneToOneMigrate(b body, typeOf reflect.Type) any {
 
 	mto := new(model.OneToOneMigrate)
 
-	mto.TargetTable = utils.TableNamePattern(typeOf.Name())
-	mto.TargetColumn = utils.ColumnNamePattern(b.prefixName)
+	targetPk, _ := typeOf.FieldByName(b.prefixName)
+	mto.TargetTable = getTableName(b.tables, typeOf, b.driver)
+	mto.TargetColumn = getColumnName(targetPk, b.driver)
 	mto.TargetSchema = b.schemasMap[typeOf.Name()]
 	mto.EscapingTargetTable = b.driver.KeywordHandler(mto.TargetTable)
 	mto.EscapingTargetColumn = b.driver.KeywordHandler(mto.TargetColumn)
 
-	mto.Name = utils.ColumnNamePattern(b.fieldName)
+	mto.Name = getColumnName(b.migrate.field, b.driver)
 	mto.EscapingName = b.driver.KeywordHandler(mto.Name)
+	mto.FieldName = b.migrate.field.Name
+	mto.ForeignKeyName = b.driver.GetDatabaseConfig().GetNamingStrategy().ForeignKeyName(b.migrate.table.Name, mto.Name)
+	mto.EscapingForeignKeyName = b.driver.KeywordHandler(mto.ForeignKeyName)
 	mto.Nullable = b.nullable
 	if err := checkIndex(b, mto.AttributeMigrate, true); err != nil {
-		panic(err)
+		return err
 	}
 	return mto
 }
@@ -216,7 +325,8 @@ func migratePk(typeOf reflect.Type, driver model.Driver) ([]*model.PrimaryKeyMig
 	pks := make([]*model.PrimaryKeyMigrate, len(fields))
 	fieldsNames := make([]string, len(fields))
 	for i := range fields {
-		pks[i] = createMigratePk(fields[i].Name, isAutoIncrement(fields[i]), getTagType(fields[i]), getTagValue(fields[i].Tag.Get("goe"), "default:"), driver)
+		pks[i] = createMigratePk(getColumnName(fields[i], driver), isAutoIncrement(fields[i]), getTagType(fields[i]), getTagValue(fields[i].Tag.Get("goe"), "default:"), driver)
+		pks[i].FieldName = fields[i].Name
 		fieldsNames[i] = fields[i].Name
 	}
 	return pks, fieldsNames, nil
@@ -228,12 +338,13 @@ func isAutoIncrement(id reflect.StructField) bool {
 
 func migrateAtt(b body) error {
 	at := createMigrateAtt(
-		b.migrate.field.Name,
+		getColumnName(b.migrate.field, b.driver),
 		getTagType(b.migrate.field),
 		b.nullable,
 		getTagValue(b.migrate.field.Tag.Get("goe"), "default:"),
 		b.driver,
 	)
+	at.FieldName = b.migrate.field.Name
 	b.migrate.table.Attributes = append(b.migrate.table.Attributes, at)
 
 	return checkIndex(b, at, false)
@@ -287,8 +398,8 @@ func getIndexValue(valueTag string, tag string) string {
 func createMigratePk(attributeName string, autoIncrement bool, dataType, defaultTag string, driver model.Driver) *model.PrimaryKeyMigrate {
 	return &model.PrimaryKeyMigrate{
 		AttributeMigrate: model.AttributeMigrate{
-			Name:         utils.ColumnNamePattern(attributeName),
-			EscapingName: driver.KeywordHandler(utils.ColumnNamePattern(attributeName)),
+			Name:         attributeName,
+			EscapingName: driver.KeywordHandler(attributeName),
 			DataType:     dataType,
 			Default:      defaultTag,
 		},
@@ -298,8 +409,8 @@ func createMigratePk(attributeName string, autoIncrement bool, dataType, default
 
 func createMigrateAtt(attributeName string, dataType string, nullable bool, defaultValue string, driver model.Driver) model.AttributeMigrate {
 	return model.AttributeMigrate{
-		Name:         utils.ColumnNamePattern(attributeName),
-		EscapingName: driver.KeywordHandler(utils.ColumnNamePattern(attributeName)),
+		Name:         attributeName,
+		EscapingName: driver.KeywordHandler(attributeName),
 		DataType:     dataType,
 		Nullable:     nullable,
 		Default:      defaultValue,
@@ -307,11 +418,13 @@ func createMigrateAtt(attributeName string, dataType string, nullable bool, defa
 }
 
 func helperAttributeMigrate(b body) error {
-	table, prefix := foreignKeyNamePattern(b.tables, b.migrate.field.Name)
+	table, prefix := getForeignKey(b.tables, b.migrate.field)
 	if table != "" {
 		b.stringInfos = stringInfos{prefixName: prefix, tableName: table, fieldName: b.migrate.field.Name}
-		if mto := isManyToOne(b, createManyToOneMigrate, createOneToOneMigrate); mto != nil {
+		if mto := getRelation(b, createManyToOneMigrate, createOneToOneMigrate); mto != nil {
 			switch v := mto.(type) {
+			case error:
+				return v
 			case *model.ManyToOneMigrate:
 				if v == nil {
 					return migrateAtt(b)
@@ -335,13 +448,14 @@ func helperAttributeMigrate(b body) error {
 }
 
 func checkIndex(b body, at model.AttributeMigrate, skipUnique bool) error {
+	defaultName := b.driver.GetDatabaseConfig().GetNamingStrategy().IndexName(b.migrate.table.Name, b.migrate.field.Name)
 	indexFunc := getIndex(b.migrate.field)
 	if indexFunc != "" {
 		for _, index := range strings.Split(indexFunc, ",") {
 			indexName := getIndexValue(index, "n:")
 
 			if indexName == "" {
-				indexName = b.migrate.table.Name + "_idx_" + strings.ToLower(b.migrate.field.Name)
+				indexName = defaultName
 			}
 			in := model.IndexMigrate{
 				Name:         b.migrate.table.Name + "_" + indexName,
@@ -358,7 +472,7 @@ func checkIndex(b body, at model.AttributeMigrate, skipUnique bool) error {
 				if c := slices.IndexFunc(b.migrate.table.Indexes, func(i model.IndexMigrate) bool {
 					return i.Name == in.Name && (i.Unique != in.Unique || i.Func != in.Func)
 				}); c != -1 {
-					return fmt.Errorf(`goe: struct "%v" have two or more indexes with same name but different uniqueness/function "%v"`, b.migrate.table.Name, in.Name)
+					return fmt.Errorf("goe: struct %q field %q have a index with same name but different uniqueness/function %q", b.valueOf.Type().Name(), b.migrate.field.Name, in.Name)
 				}
 
 				b.migrate.table.Indexes = append(b.migrate.table.Indexes, in)
@@ -371,8 +485,8 @@ func checkIndex(b body, at model.AttributeMigrate, skipUnique bool) error {
 	tagValue := b.migrate.field.Tag.Get("goe")
 	if !skipUnique && tagValueExist(tagValue, "unique") {
 		in := model.IndexMigrate{
-			Name:         b.migrate.table.Name + "_idx_" + strings.ToLower(b.migrate.field.Name),
-			EscapingName: b.driver.KeywordHandler(b.migrate.table.Name + "_idx_" + strings.ToLower(b.migrate.field.Name)),
+			Name:         defaultName,
+			EscapingName: b.driver.KeywordHandler(defaultName),
 			Unique:       true,
 			Attributes:   []model.AttributeMigrate{at},
 		}
@@ -380,9 +494,12 @@ func checkIndex(b body, at model.AttributeMigrate, skipUnique bool) error {
 	}
 
 	if tagValueExist(tagValue, "index") {
+		if slices.ContainsFunc(b.migrate.table.Indexes, func(i model.IndexMigrate) bool { return i.Name == defaultName }) {
+			return fmt.Errorf("goe: struct %q field %q have conflicting index definitions %q. try removing the unique or the index tag", b.valueOf.Type().Name(), b.migrate.field.Name, defaultName)
+		}
 		in := model.IndexMigrate{
-			Name:         b.migrate.table.Name + "_idx_" + strings.ToLower(b.migrate.field.Name),
-			EscapingName: b.driver.KeywordHandler(b.migrate.table.Name + "_idx_" + strings.ToLower(b.migrate.field.Name)),
+			Name:         defaultName,
+			EscapingName: b.driver.KeywordHandler(defaultName),
 			Unique:       false,
 			Attributes:   []model.AttributeMigrate{at},
 		}
@@ -23,11 +23,26 @@ type Driver interface {
 	Config
 }
 
+// FeatureDriver is implemented by the drivers that render the features of the model added after the baseline,
+// a driver that does not implement it supports none of them.
+type FeatureDriver interface {
+	Supports(feature enum.Feature) bool
+}
+
 type Config interface {
 	Name() string
 	GetDatabaseConfig() *DatabaseConfig
 }
 
+// NamingStrategy is used to name tables, columns, indexes and foreign keys
+// from the mapped structs.
+type NamingStrategy interface {
+	TableName(name string) string               // name is the struct name
+	ColumnName(name string) string              // name is the field name
+	IndexName(table, field string) string       // table is the table name and field the field name
+	ForeignKeyName(table, column string) string // table is the table name and column the column name
+}
+
 type Logger interface {
 	InfoContext(ctx context.Context, msg string, kv ...any)
 	WarnContext(ctx context.Context, msg string, kv ...any)
@@ -76,6 +91,14 @@ type FunctionType interface {
 	GetType() enum.FunctionType
 }
 
+// FunctionArguments is a database function with arguments, the arguments are pointers to mapped fields,
+// other functions, [FunctionArgument] or constants. A FunctionType without arguments
+// uses the field of the [Attributer] as the only argument
+type FunctionArguments interface {
+	FunctionType
+	GetArguments() []any
+}
+
 type ValueOperation interface {
 	GetValue() any
 }
@@ -84,3 +107,24 @@ type Attributer interface {
 	Attribute(Body) Attribute
 	GetField() any
 }
+
+// Expressioner is a arithmetic expression, the operands are pointers to mapped fields, functions,
+// other expressions or a expression without operator used as argument
+type Expressioner interface {
+	ValueOperation
+	GetOperator() enum.ArithmeticType
+	GetOperands() (left, right any)
+}
+
+// Caser is a conditional expression, the values are pointers to mapped fields, functions, expressions or arguments
+type Caser interface {
+	GetWhens() (conditions []Where, values []any)
+	GetElse() (value any, ok bool)
+}
+
+// Windower is a window function, the fields of the partition and the order are mapped as attributes by the select
+type Windower interface {
+	Attributer
+	Partition() []any
+	Order() []WindowOrder
+}
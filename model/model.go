@@ -2,16 +2,78 @@ package model
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/utils"
 )
 
 type Attribute struct {
 	Table         string
 	Name          string
 	AggregateType enum.AggregateType
-	FunctionType  enum.FunctionType
+	FunctionType  enum.FunctionType // Deprecated: use Function, set with Function only for the functions of a single column
+	Function      *Function         // function rendered instead of the column, the table and name are of the first column used by the function
+	Window        *Window           // over clause of a window function, the attribute is the argument of lag, lead and the aggregates
+	Expression    *Expression       // arithmetic rendered instead of the column, on update the value set on the column
+	Case          *Case             // conditional expression rendered instead of the column
+}
+
+// Expression is a arithmetic operation, the arguments of the left operand are before the arguments of the right operand
+type Expression struct {
+	Operator enum.ArithmeticType
+	Left     Operand
+	Right    Operand
+}
+
+// Operand is a operand of a expression, the Value is a argument of the query if Attribute and Expression are nil
+type Operand struct {
+	Attribute  *Attribute // column or function
+	Expression *Expression
+	Value      any
+}
+
+// Case is a conditional expression, the value of the first when with a true condition or the else value.
+// The arguments of each when are the arguments of the condition followed by the then value, the else value is the last argument
+type Case struct {
+	Whens []When
+	Else  *Operand // nil renders the case without else, the value is null if no condition is true
+}
+
+// When is a condition of a case and the value used if the condition is true
+type When struct {
+	Condition *Where
+	Index     int // position of the first argument of the condition on the query
+	Then      Operand
+}
+
+// Function is a call of a database function, the arguments are columns, constants or other functions
+type Function struct {
+	Type      enum.FunctionType
+	Arguments []FunctionArgument
+}
+
+// FunctionArgument is a argument of a function, only one of the fields is set
+type FunctionArgument struct {
+	Attribute *Attribute
+	Function  *Function
+	Value     any    // value sent as a argument of the query, the arguments of a function are on the order of the function arguments
+	Keyword   string // rendered as is, the data type of cast and the part of date part and date trunc
+}
+
+// Window is a window function, the rows of the partition are ordered by OrderBy
+type Window struct {
+	Type        enum.WindowType
+	Offset      int // rows before the current row on lag and after on lead
+	PartitionBy []Attribute
+	OrderBy     []OrderBy
+}
+
+// WindowOrder is a field used to order the rows of a window
+type WindowOrder struct {
+	Field any
+	Desc  bool
 }
 
 type JoinArgument struct {
@@ -24,6 +86,14 @@ type Join struct {
 	FirstArgument  JoinArgument
 	JoinOperation  enum.JoinType
 	SecondArgument JoinArgument
+	Composite      []JoinComposite // other arguments of a join by a composite foreign key, compared with and
+	On             *Where          // condition of the join used instead of the arguments, nil if the join is by the arguments or is a cross join
+	OnIndex        int             // position of the first argument of On, the arguments of the joins are before the where arguments
+}
+
+type JoinComposite struct {
+	FirstArgument  JoinArgument
+	SecondArgument JoinArgument
 }
 
 type Where struct {
@@ -31,8 +101,9 @@ type Where struct {
 	Attribute           Attribute
 	Operator            enum.OperatorType
 	AttributeValue      Attribute
-	SizeIn              uint
-	QueryIn             *Query
+	SizeIn              uint   // number of arguments of in, between has always two arguments
+	QueryIn             *Query // subquery of in and exists
+	MergedIn            bool   // the arguments of QueryIn are merged on the query and the WhereIndex of QueryIn is the position of the first argument on the query, always set on exists
 	Value               ValueOperation
 	Arg                 any
 	TableId             int
@@ -46,15 +117,18 @@ type Where struct {
 type OrderBy struct {
 	Desc      bool
 	Attribute Attribute
+	Position  int // position of the selected attribute starting at 1, used instead of the attribute by cases, expressions and functions with arguments
 }
 
 type GroupBy struct {
 	Attribute Attribute
+	Position  int // position of the selected attribute starting at 1, used instead of the attribute by cases, expressions and functions with arguments
 }
 
 type Table struct {
 	Schema *string
 	Name   string
+	Alias  string // the attributes and joins reference the table by the alias if not empty, rendered by the drivers with enum.AliasFeature after the table
 }
 
 func (t Table) String() string {
@@ -64,21 +138,36 @@ func (t Table) String() string {
 	return t.Name
 }
 
+// Reference returns the name used by the attributes to reference the table, the alias if the table is aliased
+func (t Table) Reference() string {
+	if t.Alias != "" {
+		return t.Alias
+	}
+	return t.Name
+}
+
 type Query struct {
 	Type       enum.QueryType
 	Attributes []Attribute
 	Tables     []Table
 
-	Joins     []Join    //Select
-	Limit     int       //Select
-	Offset    int       //Select
-	OrderBy   []OrderBy //Select
-	GroupBy   []GroupBy //Select
-	ForUpdate bool      //Select
+	Joins      []Join      //Select
+	Limit      int         //Select
+	Offset     int         //Select
+	OrderBy    []OrderBy   //Select
+	GroupBy    []GroupBy   //Select
+	ForUpdate  bool        //Select
+	Distinct   bool        //Select
+	DistinctOn []Attribute //Select, only for dialects that support DISTINCT ON (e.g. PostgreSQL)
+	FromQuery  *Query      //Select, the rows of the query are used as table (e.g. SELECT COUNT(*) FROM (query))
+	Compound   []Compound  //Select, the order by, limit and offset apply to the combined rows and the order by references the columns of the first query
+	With       []With      //Select, common table expressions, WITH RECURSIVE if any is recursive. The arguments are before all the arguments of the query
 
 	WhereOperations []Where //Select, Update and Delete
 	Where           *Where  //Select, Update and Delete
 	WhereIndex      int     //Start of where position arguments $1, $2...
+	AttributeIndex  int     //Select, start of the arguments of the attributes expressions and functions, after the with arguments
+	Having          *Where  //Select, the arguments are after the where arguments
 	Arguments       []any
 
 	ReturningID    *Attribute //Insert
@@ -89,6 +178,21 @@ type Query struct {
 	Header QueryHeader
 }
 
+// Compound is a query combined by a set operation with the previous queries,
+// the arguments of the compound queries are after the arguments of the query
+type Compound struct {
+	Type  enum.CompoundType
+	Query Query
+}
+
+// With is a common table expression, the query of a recursive expression is the anchor combined by union all with the recursive query
+type With struct {
+	Name      string
+	Columns   []string
+	Recursive bool
+	Query     Query
+}
+
 type QueryHeader struct {
 	Err           error
 	ModelBuild    time.Duration
@@ -97,7 +201,7 @@ type QueryHeader struct {
 
 type Set struct {
 	Attribute any
-	Value     any
+	Value     any // value of the attribute or a [Expressioner] calculated by the database
 }
 
 type Body struct {
@@ -114,6 +218,7 @@ type Migrator struct {
 type TableMigrate struct {
 	Name         string
 	EscapingName string
+	StructName   string // name of the mapped struct
 	Schema       *string
 	Migrated     bool
 	PrimaryKeys  []PrimaryKeyMigrate
@@ -148,17 +253,20 @@ type AttributeMigrate struct {
 	Nullable     bool
 	Name         string
 	EscapingName string
+	FieldName    string // name of the mapped struct field
 	DataType     string
 	Default      string
 }
 
 type OneToOneMigrate struct {
 	AttributeMigrate
-	TargetTable          string
-	TargetColumn         string
-	EscapingTargetTable  string
-	EscapingTargetColumn string
-	TargetSchema         *string
+	ForeignKeyName         string
+	EscapingForeignKeyName string
+	TargetTable            string
+	TargetColumn           string
+	EscapingTargetTable    string
+	EscapingTargetColumn   string
+	TargetSchema           *string
 }
 
 // Returns the target table and the schema.
@@ -171,11 +279,22 @@ func (o OneToOneMigrate) EscapingTargetTableName() string {
 
 type ManyToOneMigrate struct {
 	AttributeMigrate
-	TargetTable          string
+	ForeignKeyName         string
+	EscapingForeignKeyName string
+	TargetTable            string
+	TargetColumn           string
+	EscapingTargetTable    string
+	EscapingTargetColumn   string
+	TargetSchema           *string
+	Composite              []CompositeMigrate // other columns of a composite foreign key, each one is also on the table Attributes
+}
+
+// A column of a composite foreign key and the referenced column.
+type CompositeMigrate struct {
+	Name                 string
+	EscapingName         string
 	TargetColumn         string
-	EscapingTargetTable  string
 	EscapingTargetColumn string
-	TargetSchema         *string
 }
 
 // Returns the target table and the schema.
@@ -186,15 +305,84 @@ func (m ManyToOneMigrate) EscapingTargetTableName() string {
 	return m.EscapingTargetTable
 }
 
+// Metadata of the tables mapped by the database, returned by goe.DB.Metadata
+type Metadata struct {
+	Schemas []string
+	Tables  []TableMetadata
+}
+
+// Returns the table mapped by the struct name.
+func (m Metadata) Table(structName string) (TableMetadata, bool) {
+	for _, t := range m.Tables {
+		if t.StructName == structName {
+			return t, true
+		}
+	}
+	return TableMetadata{}, false
+}
+
+type TableMetadata struct {
+	Name       string
+	StructName string
+	Schema     *string
+	Columns    []ColumnMetadata // primary keys first, then the other columns
+	Relations  []RelationMetadata
+	Indexes    []IndexMetadata
+}
+
+// Returns the column mapped by the field name.
+func (t TableMetadata) Column(fieldName string) (ColumnMetadata, bool) {
+	for _, c := range t.Columns {
+		if c.FieldName == fieldName {
+			return c, true
+		}
+	}
+	return ColumnMetadata{}, false
+}
+
+type ColumnMetadata struct {
+	Name          string
+	FieldName     string
+	GoType        reflect.Type // type of the struct field, a pointer if the field is nullable
+	DataType      string
+	Nullable      bool
+	Default       string
+	PrimaryKey    bool
+	AutoIncrement bool
+}
+
+type RelationMetadata struct {
+	Type           enum.RelationType
+	ForeignKeyName string
+	Columns        []string // more than one column on a composite foreign key
+	TargetSchema   *string
+	TargetTable    string
+	TargetColumns  []string
+}
+
+type IndexMetadata struct {
+	Name    string
+	Unique  bool
+	Func    string
+	Columns []string
+}
+
 // Database config used by all GOE drivers
 type DatabaseConfig struct {
 	Logger           Logger
-	IncludeArguments bool          // include all arguments used on query
-	QueryThreshold   time.Duration // query threshold to warning on slow queries
-	databaseName     string
-	errorTranslator  func(err error) error
-	schemas          []string
-	initCallback     func() error
+	IncludeArguments bool           // include all arguments used on query
+	QueryThreshold   time.Duration  // query threshold to warning on slow queries
+	NamingStrategy   NamingStrategy // naming used for tables, columns, indexes and foreign keys
+	// CursorSecret is the key used to sign the cursors of AsCursor.
+	//
+	// WARNING: if empty a random key is generated on each Open, so the cursors are rejected
+	// with ErrInvalidCursor after a restart and by the other instances of the application.
+	// Set the same secret on every instance to keep the cursors valid.
+	CursorSecret    []byte
+	databaseName    string
+	errorTranslator func(err error) error
+	schemas         []string
+	initCallback    func() error
 }
 
 func (c DatabaseConfig) ErrorHandler(ctx context.Context, err error) error {
@@ -243,6 +431,14 @@ func (c DatabaseConfig) InfoHandler(ctx context.Context, query Query) {
 	c.Logger.InfoContext(ctx, "query_runned", logs...)
 }
 
+// GetNamingStrategy returns the naming strategy, if none is set returns [utils.DefaultNamingStrategy]
+func (c DatabaseConfig) GetNamingStrategy() NamingStrategy {
+	if c.NamingStrategy == nil {
+		return utils.DefaultNamingStrategy{}
+	}
+	return c.NamingStrategy
+}
+
 func (c DatabaseConfig) Schemas() []string {
 	return c.schemas
 }
@@ -0,0 +1,72 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/where"
+)
+
+func TestWhereBetween(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := List(db.Animal).Where(where.And(where.Between(&db.Animal.Age, 2, 5), where.Equals(&db.Animal.Name, "cat"))).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected between, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	if q.Where.FirstOperation.Operator != enum.Between {
+		t.Errorf("Expected between, got %+v", q.Where.FirstOperation)
+	}
+	if len(q.Arguments) != 3 || q.Arguments[0] != 2 || q.Arguments[1] != 5 || q.Arguments[2] != "cat" {
+		t.Errorf("Expected the bounds before the next argument, got %v", q.Arguments)
+	}
+
+	_, err = List(db.Animal).Where(where.ILike(&db.Animal.Name, "%Cat%")).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected ilike, got error %v", err)
+	}
+	if q = d.lastQuery(t); q.Where.Operator != enum.ILike || q.Arguments[0] != "%Cat%" {
+		t.Errorf("Expected ilike sent to the driver, got %+v %v", q.Where, q.Arguments)
+	}
+}
+
+func TestWhereOperatorsBaseline(t *testing.T) {
+	db, d := openTest(t, true)
+
+	_, err := List(db.Animal).Where(where.ILike(&db.Animal.Name, "%Cat%")).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected ilike, got error %v", err)
+	}
+	q := d.lastQuery(t)
+	if q.Where.Operator != enum.Like || q.Where.Attribute.Function == nil || q.Where.Attribute.Function.Type != enum.LowerFunction || q.Arguments[0] != "%cat%" {
+		t.Errorf("Expected like of the lowercase column and value, got %+v %v", q.Where, q.Arguments)
+	}
+
+	_, err = List(db.Animal).Where(where.IsNull(&db.Animal.Name)).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected is null, got error %v", err)
+	}
+	if q = d.lastQuery(t); q.Where.Operator != enum.Is || len(q.Arguments) != 0 {
+		t.Errorf("Expected is null without arguments, got %+v %v", q.Where, q.Arguments)
+	}
+
+	for _, w := range []struct {
+		desc  string
+		where func() error
+	}{
+		{desc: "between", where: func() error {
+			_, err := List(db.Animal).Where(where.Between(&db.Animal.Age, 2, 5)).AsSlice()
+			return err
+		}},
+		{desc: "regex", where: func() error {
+			_, err := List(db.Animal).Where(where.Regex(&db.Animal.Name, "^C")).AsSlice()
+			return err
+		}},
+	} {
+		if err = w.where(); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Expected ErrUnsupported on %v, got %v", w.desc, err)
+		}
+	}
+}
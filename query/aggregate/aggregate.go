@@ -60,6 +60,31 @@ func Sum(t any) *sum {
 	return &sum{Field: t}
 }
 
+// Argument is used to pass a value to the aggregate a inside a having clause,
+// a only sets the type of the argument
+//
+// # Example
+//
+//	// habitats with more than 10 animals
+//	count := aggregate.Count(&db.Animal.Id)
+//	Having(where.Greater(count, aggregate.Argument(count, 10)))
+func Argument[A count | avg | max | min | sum](a *A, value float64) A {
+	var argument A
+	switch arg := any(&argument).(type) {
+	case *count:
+		arg.Value = int64(value)
+	case *avg:
+		arg.Value = value
+	case *max:
+		arg.Value = value
+	case *min:
+		arg.Value = value
+	case *sum:
+		arg.Value = value
+	}
+	return argument
+}
+
 type count struct {
 	Field any
 	Value int64
@@ -81,6 +106,10 @@ func (c count) GetField() any {
 	return c.Field
 }
 
+func (c count) GetValue() any {
+	return c.Value
+}
+
 type avg struct {
 	Field any
 	Value float64
@@ -102,6 +131,10 @@ func (a avg) GetField() any {
 	return a.Field
 }
 
+func (a avg) GetValue() any {
+	return a.Value
+}
+
 type max struct {
 	Field any
 	Value float64
@@ -123,6 +156,10 @@ func (m max) GetField() any {
 	return m.Field
 }
 
+func (m max) GetValue() any {
+	return m.Value
+}
+
 type min struct {
 	Field any
 	Value float64
@@ -144,6 +181,10 @@ func (m min) GetField() any {
 	return m.Field
 }
 
+func (m min) GetValue() any {
+	return m.Value
+}
+
 type sum struct {
 	Field any
 	Value float64
@@ -164,3 +205,7 @@ func (s sum) Attribute(b model.Body) model.Attribute {
 func (s sum) GetField() any {
 	return s.Field
 }
+
+func (s sum) GetValue() any {
+	return s.Value
+}
@@ -0,0 +1,142 @@
+package expr
+
+import (
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+)
+
+// Add uses database arithmetic to sum left and right, right can be a field,
+// other expression or a value passed by Argument
+//
+// # Example
+//
+//	// set views = views + 1
+//	goe.Update(db.Post).Sets(update.Expression(&db.Post.Views, expr.Add(&db.Post.Views, expr.Argument(1)))).
+//		Where(where.Equals(&db.Post.Id, 2))
+func Add[T any, B *T | **T | *expression[T] | expression[T]](left *T, right B) *expression[T] {
+	return &expression[T]{Operator: enum.AddArithmetic, Left: left, Right: right}
+}
+
+// Sub uses database arithmetic to subtract right from left, right can be a field,
+// other expression or a value passed by Argument
+//
+// # Example
+//
+//	// products with stock not reserved
+//	goe.List(db.Product).Where(where.Greater(expr.Sub(&db.Product.Stock, &db.Product.Reserved), expr.Argument(0)))...
+func Sub[T any, B *T | **T | *expression[T] | expression[T]](left *T, right B) *expression[T] {
+	return &expression[T]{Operator: enum.SubArithmetic, Left: left, Right: right}
+}
+
+// Mul uses database arithmetic to multiply left by right, right can be a field,
+// other expression or a value passed by Argument
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Product string
+//		Total   float64
+//	}](&db.Line.Product, expr.Mul(&db.Line.Price, &db.Line.Qty))...
+func Mul[T any, B *T | **T | *expression[T] | expression[T]](left *T, right B) *expression[T] {
+	return &expression[T]{Operator: enum.MulArithmetic, Left: left, Right: right}
+}
+
+// Div uses database arithmetic to divide left by right, right can be a field,
+// other expression or a value passed by Argument. The division of integers is a integer
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Product string
+//		Price   float64
+//	}](&db.Line.Product, expr.Div(&db.Line.Total, &db.Line.Qty))...
+func Div[T any, B *T | **T | *expression[T] | expression[T]](left *T, right B) *expression[T] {
+	return &expression[T]{Operator: enum.DivArithmetic, Left: left, Right: right}
+}
+
+// Mod uses database arithmetic to get the remainder of left divided by right, right can be a field,
+// other expression or a value passed by Argument
+//
+// # Example
+//
+//	// animals with even id
+//	goe.List(db.Animal).Where(where.Equals(expr.Mod(&db.Animal.Id, expr.Argument(2)), expr.Argument(0)))...
+func Mod[T any, B *T | **T | *expression[T] | expression[T]](left *T, right B) *expression[T] {
+	return &expression[T]{Operator: enum.ModArithmetic, Left: left, Right: right}
+}
+
+// Argument is used to pass a value to a expression as operand or as the value of a where
+//
+// # Example
+//
+//	goe.List(db.Product).Where(where.Greater(expr.Sub(&db.Product.Stock, &db.Product.Reserved), expr.Argument(0)))...
+func Argument[T any](value T) expression[T] {
+	return expression[T]{Value: value}
+}
+
+// Case uses database conditional expression to get the value of the first when with a true condition,
+// or the else value. The values can be fields, functions, expressions or values passed as arguments.
+// Selected cases can be used on order by and group by
+//
+// # Example
+//
+//	status := expr.Case().When(where.Equals(&db.User.Status, 1), "active").Else("inactive")
+//	goe.Select[struct {
+//		Status string
+//		Users  int64
+//	}](status, aggregate.Count(&db.User.Id)).GroupBy(status)...
+func Case() *caseExpression {
+	return &caseExpression{}
+}
+
+type caseExpression struct {
+	conditions []model.Where
+	values     []any
+	elseValue  any
+	hasElse    bool
+}
+
+// When adds a condition to the case, value is the result of the case if the condition is the first true condition
+func (c *caseExpression) When(condition model.Where, value any) *caseExpression {
+	c.conditions = append(c.conditions, condition)
+	c.values = append(c.values, value)
+	return c
+}
+
+// Else sets the result of the case if no condition is true, without else the result is null
+func (c *caseExpression) Else(value any) *caseExpression {
+	c.elseValue, c.hasElse = value, true
+	return c
+}
+
+func (c caseExpression) GetWhens() (conditions []model.Where, values []any) {
+	return c.conditions, c.values
+}
+
+func (c caseExpression) GetElse() (value any, ok bool) {
+	return c.elseValue, c.hasElse
+}
+
+type expression[T any] struct {
+	Operator enum.ArithmeticType
+	Left     any
+	Right    any
+	Value    T
+}
+
+func (e expression[T]) GetOperator() enum.ArithmeticType {
+	return e.Operator
+}
+
+func (e expression[T]) GetOperands() (left, right any) {
+	return e.Left, e.Right
+}
+
+func (e expression[T]) GetValue() any {
+	return e.Value
+}
+
+// GetResult returns the value of a argument, used to check the type of the expression
+func (e expression[T]) GetResult() T {
+	return e.Value
+}
@@ -1,6 +1,9 @@
 package function
 
 import (
+	"reflect"
+	"time"
+
 	"github.com/go-goe/goe/enum"
 	"github.com/go-goe/goe/model"
 )
@@ -14,8 +17,8 @@ import (
 //	}{
 //		UpperName: function.ToUpper(&db.Animal.Name),
 //	})
-func ToUpper(target *string) *function[string] {
-	return &function[string]{Field: target, Type: enum.UpperFunction}
+func ToUpper[A *string | *function[string]](target A) *function[string] {
+	return &function[string]{Field: fieldOf[string](target), Type: enum.UpperFunction, Arguments: []any{target}}
 }
 
 // ToLower uses database function to converts the target string to lowercase
@@ -27,8 +30,149 @@ func ToUpper(target *string) *function[string] {
 //	}{
 //		LowerName: function.ToLower(&db.Animal.Name),
 //	})
-func ToLower(target *string) *function[string] {
-	return &function[string]{Field: target, Type: enum.LowerFunction}
+func ToLower[A *string | *function[string]](target A) *function[string] {
+	return &function[string]{Field: fieldOf[string](target), Type: enum.LowerFunction, Arguments: []any{target}}
+}
+
+// Trim uses database function to remove the spaces from both ends of the target string
+//
+// # Example
+//
+//	// lower(trim(animals.name))
+//	goe.Select[struct {
+//		Name string
+//	}](function.ToLower(function.Trim(&db.Animal.Name)))...
+func Trim[A *string | *function[string]](target A) *function[string] {
+	return &function[string]{Field: fieldOf[string](target), Type: enum.TrimFunction, Arguments: []any{target}}
+}
+
+// Length uses database function to get the number of characters of the target string
+//
+// # Example
+//
+//	goe.Select(db.Animal).Where(where.Greater(function.Length(&db.Animal.Name), function.Argument[int64](3)))...
+func Length[A *string | *function[string]](target A) *function[int64] {
+	return &function[int64]{Type: enum.LengthFunction, Arguments: []any{target}}
+}
+
+// Substring uses database function to get length characters of the target string,
+// starting at the position start. The first character is at position 1
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Initial string
+//	}](function.Substring(&db.Animal.Name, 1, 1))...
+func Substring[A *string | *function[string]](target A, start, length int) *function[string] {
+	return &function[string]{Field: fieldOf[string](target), Type: enum.SubstringFunction, Arguments: []any{
+		target, model.FunctionArgument{Value: start}, model.FunctionArgument{Value: length}}}
+}
+
+// Concat uses database function to concatenate the targets, the targets can be fields,
+// functions or string constants
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Label string
+//	}](function.Concat(&db.Animal.Name, " - ", &db.Habitat.Name))...
+func Concat(targets ...any) *function[string] {
+	return &function[string]{Type: enum.ConcatFunction, Arguments: targets}
+}
+
+// Coalesce uses database function to get the target, or value if the target is null
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Nickname string
+//	}](function.Coalesce(&db.User.Nickname, "anonymous"))...
+func Coalesce[T any, A *T | **T | *function[T]](target A, value T) *function[T] {
+	return &function[T]{Field: fieldOf[T](target), Type: enum.CoalesceFunction, Arguments: []any{target, model.FunctionArgument{Value: value}}}
+}
+
+// Abs uses database function to get the absolute value of the target
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Balance float64
+//	}](function.Abs(&db.Account.Balance))...
+func Abs(target any) *function[float64] {
+	return &function[float64]{Field: fieldOf[float64](target), Type: enum.AbsFunction, Arguments: []any{target}}
+}
+
+// Round uses database function to round the target to the number of decimal places digits
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Result float64
+//	}](function.Round(&db.Exam.Result, 2))...
+func Round(target any, digits int) *function[float64] {
+	return &function[float64]{Field: fieldOf[float64](target), Type: enum.RoundFunction, Arguments: []any{target, model.FunctionArgument{Value: digits}}}
+}
+
+// Cast uses database cast to convert the target to the database type dataType,
+// the result is scanned as T. The dataType is written on the query, so it needs to be
+// a type name with a optional size (e.g. integer, varchar(20), numeric(10, 2)), other values panic on the query
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Code string
+//	}](function.Cast[string](&db.Animal.Id, "varchar(20)"))...
+func Cast[T any](target any, dataType string) *function[T] {
+	return &function[T]{Field: fieldOf[T](target), Type: enum.CastFunction, Arguments: []any{target, model.FunctionArgument{Keyword: dataType}}}
+}
+
+// Now uses database function to get the current date and time
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Now  time.Time
+//	}](&db.Animal.Name, function.Now())...
+func Now() *function[time.Time] {
+	return &function[time.Time]{Type: enum.NowFunction}
+}
+
+type datePart string
+
+// Parts of a date used by DatePart and DateTrunc
+const (
+	Year   datePart = "year"
+	Month  datePart = "month"
+	Day    datePart = "day"
+	Hour   datePart = "hour"
+	Minute datePart = "minute"
+	Second datePart = "second"
+)
+
+// DatePart uses database function to get the part of the target date
+//
+// # Example
+//
+//	// animals born on 2024
+//	goe.List(db.Animal).Where(where.Equals(function.DatePart(function.Year, &db.Animal.Birth), function.Argument[float64](2024)))...
+func DatePart[A *time.Time | **time.Time | *function[time.Time]](part datePart, target A) *function[float64] {
+	return &function[float64]{Type: enum.DatePartFunction, Arguments: []any{model.FunctionArgument{Keyword: string(part)}, target}}
+}
+
+// DateTrunc uses database function to truncate the target date to the part,
+// the smaller parts are set to zero
+//
+// # Example
+//
+//	// sales by month
+//	goe.Select[struct {
+//		Month time.Time
+//		Total float64
+//	}](function.DateTrunc(function.Month, &db.Sale.Day), aggregate.Sum(&db.Sale.Total)).
+//		GroupBy(function.DateTrunc(function.Month, &db.Sale.Day))...
+func DateTrunc[A *time.Time | **time.Time | *function[time.Time]](part datePart, target A) *function[time.Time] {
+	return &function[time.Time]{Field: fieldOf[time.Time](target), Type: enum.DateTruncFunction, Arguments: []any{model.FunctionArgument{Keyword: string(part)}, target}}
 }
 
 // Argument is used to pass a value to a function inside a where clause
@@ -41,9 +185,17 @@ func Argument[T any](value T) function[T] {
 }
 
 type function[T any] struct {
-	Field *T
-	Type  enum.FunctionType
-	Value T
+	// Deprecated: use Arguments, Field is the target of the function if the target is a field of type T.
+	Field     *T
+	Type      enum.FunctionType
+	Arguments []any
+	Value     T
+}
+
+// fieldOf returns the target as the Field of the function, nil if the target is not a field of type T
+func fieldOf[T any](target any) *T {
+	field, _ := target.(*T)
+	return field
 }
 
 func (f function[T]) GetValue() any {
@@ -54,14 +206,48 @@ func (f function[T]) GetType() enum.FunctionType {
 	return f.Type
 }
 
+func (f function[T]) GetArguments() []any {
+	return f.Arguments
+}
+
 func (f function[T]) Attribute(b model.Body) model.Attribute {
 	return model.Attribute{
 		Table:        b.Table,
 		Name:         b.Name,
+		Function:     f.function(b),
 		FunctionType: f.Type,
 	}
 }
 
+// function returns the model of the function, the fields used as arguments are the column of b
+func (f function[T]) function(b model.Body) *model.Function {
+	function := &model.Function{Type: f.Type, Arguments: make([]model.FunctionArgument, 0, len(f.Arguments))}
+	for _, arg := range f.Arguments {
+		switch a := arg.(type) {
+		case model.FunctionArgument:
+			function.Arguments = append(function.Arguments, a)
+		case model.Attributer:
+			nested := a.Attribute(b)
+			if nested.Function != nil {
+				function.Arguments = append(function.Arguments, model.FunctionArgument{Function: nested.Function})
+				continue
+			}
+			function.Arguments = append(function.Arguments, model.FunctionArgument{Attribute: &nested})
+		default:
+			if reflect.ValueOf(arg).Kind() == reflect.Pointer {
+				function.Arguments = append(function.Arguments, model.FunctionArgument{Attribute: &model.Attribute{Table: b.Table, Name: b.Name}})
+				continue
+			}
+			function.Arguments = append(function.Arguments, model.FunctionArgument{Value: arg})
+		}
+	}
+	return function
+}
+
+// GetField returns the target of the function, nil if the function has no arguments
 func (f function[T]) GetField() any {
-	return f.Field
+	if len(f.Arguments) == 0 {
+		return nil
+	}
+	return f.Arguments[0]
 }
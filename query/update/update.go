@@ -12,3 +12,17 @@ import "github.com/go-goe/goe/model"
 func Set[T any, A *T | **T](a A, v T) model.Set {
 	return model.Set{Attribute: a, Value: v}
 }
+
+// Expression is used inside a update to set the result of a expression from expr sub package on the column
+//
+// # Example
+//
+//	// set views = views + 1 on post of id 2
+//	err = goe.Update(db.Post).Sets(update.Expression(&db.Post.Views, expr.Add(&db.Post.Views, expr.Argument(1)))).
+//	Where(where.Equals(&db.Post.Id, 2))
+func Expression[T any, A *T | **T, E interface {
+	model.Expressioner
+	GetResult() T
+}](a A, e E) model.Set {
+	return model.Set{Attribute: a, Value: e}
+}
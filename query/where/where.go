@@ -4,20 +4,10 @@ import (
 	"reflect"
 
 	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/internal/operand"
 	"github.com/go-goe/goe/model"
 )
 
-type valueOperation struct {
-	value any
-}
-
-func (vo valueOperation) GetValue() any {
-	if result, ok := vo.value.(model.ValueOperation); ok {
-		return result.GetValue()
-	}
-	return vo.value
-}
-
 // # Example
 //
 //	// match Food with Id fc1865b4-6f2d-4cc6-b766-49c2634bf5c4
@@ -30,7 +20,7 @@ func Equals[T any, A *T | **T](a A, v T) model.Where {
 	if reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
 		return model.Where{Arg: a, Operator: enum.Is, Type: enum.OperationIsWhere}
 	}
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Equals, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Equals, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -45,7 +35,7 @@ func NotEquals[T any, A *T | **T](a A, v T) model.Where {
 	if reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
 		return model.Where{Arg: a, Operator: enum.IsNot, Type: enum.OperationIsWhere}
 	}
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.NotEquals, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.NotEquals, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -53,7 +43,7 @@ func NotEquals[T any, A *T | **T](a A, v T) model.Where {
 //	// get all animals that was created after 09 of october 2024 at 11:50AM
 //	Where(where.Greater(&db.Animal.CreateAt, time.Date(2024, time.October, 9, 11, 50, 00, 00, time.Local)))
 func Greater[T any, A *T | **T](a A, v T) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Greater, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Greater, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -61,7 +51,7 @@ func Greater[T any, A *T | **T](a A, v T) model.Where {
 //	// get all animals that was created in or after 09 of october 2024 at 11:50AM
 //	Where(where.GreaterEquals(&db.Animal.CreateAt, time.Date(2024, time.October, 9, 11, 50, 00, 00, time.Local)))
 func GreaterEquals[T any, A *T | **T](a A, v T) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.GreaterEquals, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.GreaterEquals, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -69,7 +59,7 @@ func GreaterEquals[T any, A *T | **T](a A, v T) model.Where {
 //	// get all animals that was updated before 09 of october 2024 at 11:50AM
 //	Where(where.Less(&db.Animal.UpdateAt, time.Date(2024, time.October, 9, 11, 50, 00, 00, time.Local)))
 func Less[T any, A *T | **T](a A, v T) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Less, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Less, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -77,7 +67,7 @@ func Less[T any, A *T | **T](a A, v T) model.Where {
 //	// get all animals that was updated in or before 09 of october 2024 at 11:50AM
 //	Where(where.LessEquals(&db.Animal.UpdateAt, time.Date(2024, time.October, 9, 11, 50, 00, 00, time.Local)))
 func LessEquals[T any, A *T | **T](a A, v T) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.LessEquals, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.LessEquals, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -85,7 +75,7 @@ func LessEquals[T any, A *T | **T](a A, v T) model.Where {
 //	// get all animals that has a "at" in his name
 //	Where(where.Like(&db.Animal.Name, "%at%"))
 func Like[T any](a *T, v string) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Like, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Like, Type: enum.OperationWhere}
 }
 
 // # Example
@@ -93,7 +83,89 @@ func Like[T any](a *T, v string) model.Where {
 //	// get all animals that has a "at" in his name
 //	Where(where.Like(&db.Animal.Name, "%at%"))
 func NotLike[T any](a *T, v string) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.NotLike, Type: enum.OperationWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.NotLike, Type: enum.OperationWhere}
+}
+
+// ILike is a case-insensitive [Like], on drivers without ILIKE is sent as a like of the lowercase column and value
+//
+// # Example
+//
+//	// get all animals that has a "cat" in his name, as "Cat" and "Little cat"
+//	Where(where.ILike(&db.Animal.Name, "%cat%"))
+func ILike[T any](a *T, v string) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.ILike, Type: enum.OperationWhere}
+}
+
+// NotILike is a case-insensitive [NotLike]
+//
+// # Example
+//
+//	// get all animals that don't have a "cat" in his name
+//	Where(where.NotILike(&db.Animal.Name, "%cat%"))
+func NotILike[T any](a *T, v string) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.NotILike, Type: enum.OperationWhere}
+}
+
+// Regex matches the values with the regular expression, the syntax is the one supported by the database
+//
+// # Example
+//
+//	// get all animals that the name starts with "C"
+//	Where(where.Regex(&db.Animal.Name, "^C"))
+func Regex[T any](a *T, pattern string) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: pattern}, Operator: enum.Regex, Type: enum.OperationWhere}
+}
+
+// NotRegex matches the values that don't match the regular expression
+//
+// # Example
+//
+//	// get all animals that the name don't starts with "C"
+//	Where(where.NotRegex(&db.Animal.Name, "^C"))
+func NotRegex[T any](a *T, pattern string) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: pattern}, Operator: enum.NotRegex, Type: enum.OperationWhere}
+}
+
+// Between matches the values on the closed range from and to.
+// Used on a filter, a zero bound is a open end of the range and the two bounds zero skip the operation
+//
+// # Example
+//
+//	// get all exams with score from 5 to 7.5
+//	Where(where.Between(&db.Exam.Score, 5, 7.5))
+func Between[T any, A *T | **T](a A, from, to T) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: [2]T{from, to}}, Operator: enum.Between, Type: enum.OperationBetweenWhere}
+}
+
+// NotBetween matches the values outside of the closed range from and to.
+// Used on a filter, a zero bound is a open end of the range and the two bounds zero skip the operation
+//
+// # Example
+//
+//	// get all exams with score lower than 5 or greater than 7.5
+//	Where(where.NotBetween(&db.Exam.Score, 5, 7.5))
+func NotBetween[T any, A *T | **T](a A, from, to T) model.Where {
+	return model.Where{Arg: a, Value: operand.Value{Value: [2]T{from, to}}, Operator: enum.NotBetween, Type: enum.OperationBetweenWhere}
+}
+
+// IsNull matches the null values, works with pointer and non-pointer fields
+//
+// # Example
+//
+//	// generate: WHERE "animals"."habitat_id" IS NULL
+//	Where(where.IsNull(&db.Animal.HabitatId))
+func IsNull[T any](a *T) model.Where {
+	return model.Where{Arg: a, Operator: enum.Is, Type: enum.OperationIsWhere}
+}
+
+// IsNotNull matches the non null values, works with pointer and non-pointer fields
+//
+// # Example
+//
+//	// generate: WHERE "animals"."habitat_id" IS NOT NULL
+//	Where(where.IsNotNull(&db.Animal.HabitatId))
+func IsNotNull[T any](a *T) model.Where {
+	return model.Where{Arg: a, Operator: enum.IsNot, Type: enum.OperationIsWhere}
 }
 
 // # Example
@@ -107,7 +179,7 @@ func NotLike[T any](a *T, v string) model.Where {
 //	// Use querySelect on in
 //	rows, err := goe.Select(db.Animal).Where(where.In(&db.Animal.Name, querySelect).AsSlice()
 func In[T any, V []T | model.Query](a *T, mq V) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: mq}, Operator: enum.In, Type: enum.OperationInWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: mq}, Operator: enum.In, Type: enum.OperationInWhere}
 }
 
 // # Example
@@ -121,7 +193,35 @@ func In[T any, V []T | model.Query](a *T, mq V) model.Where {
 //	// Use querySelect on not in
 //	rows, err := goe.Select(db.Animal).Where(where.NotIn(&db.Animal.Name, querySelect).AsSlice()
 func NotIn[T any, V []T | model.Query](a *T, mq V) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: mq}, Operator: enum.NotIn, Type: enum.OperationInWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: mq}, Operator: enum.NotIn, Type: enum.OperationInWhere}
+}
+
+// Exists matches the rows where the subquery returns any row, the subquery can
+// reference the tables of the outer query using the Arg operations.
+//
+// # Example
+//
+//	// match all animals with a food
+//	querySelect := goe.Select[any](&db.AnimalFood.AnimalId).
+//		Where(where.EqualsArg(&db.AnimalFood.AnimalId, &db.Animal.Id)).AsQuery()
+//
+//	rows, err := goe.List(db.Animal).Where(where.Exists(querySelect)).AsSlice()
+func Exists(q model.Query) model.Where {
+	return model.Where{Value: operand.Value{Value: q}, Operator: enum.Exists, Type: enum.OperationExistsWhere}
+}
+
+// NotExists matches the rows where the subquery returns no rows, the subquery can
+// reference the tables of the outer query using the Arg operations.
+//
+// # Example
+//
+//	// match all animals without a food
+//	querySelect := goe.Select[any](&db.AnimalFood.AnimalId).
+//		Where(where.EqualsArg(&db.AnimalFood.AnimalId, &db.Animal.Id)).AsQuery()
+//
+//	rows, err := goe.List(db.Animal).Where(where.NotExists(querySelect)).AsSlice()
+func NotExists(q model.Query) model.Where {
+	return model.Where{Value: operand.Value{Value: q}, Operator: enum.NotExists, Type: enum.OperationExistsWhere}
 }
 
 // # Example
@@ -169,40 +269,40 @@ func Or(fo, so model.Where) model.Where {
 //		),
 //	).AsSlice()
 func EqualsArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Equals, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Equals, Type: enum.OperationAttributeWhere}
 }
 
 // # Example
 //
 //	Where(where.NotEqualsArg(&db.Job.Id, &db.Person.Id))
 func NotEqualsArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.NotEquals, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.NotEquals, Type: enum.OperationAttributeWhere}
 }
 
 // # Example
 //
 //	Where(where.GreaterArg(&db.Stock.Minimum, &db.Drinks.Stock))
 func GreaterArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Greater, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Greater, Type: enum.OperationAttributeWhere}
 }
 
 // # Example
 //
 //	Where(where.GreaterEqualsArg(&db.Drinks.Reorder, &db.Drinks.Stock))
 func GreaterEqualsArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.GreaterEquals, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.GreaterEquals, Type: enum.OperationAttributeWhere}
 }
 
 // # Example
 //
 //	Where(where.LessArg(&db.Exam.Score, &db.Data.Minimum))
 func LessArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.Less, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.Less, Type: enum.OperationAttributeWhere}
 }
 
 // # Example
 //
 //	Where(where.LessEqualsArg(&db.Exam.Score, &db.Data.Minimum))
 func LessEqualsArg[T any, A *T | **T](a A, v A) model.Where {
-	return model.Where{Arg: a, Value: valueOperation{value: v}, Operator: enum.LessEquals, Type: enum.OperationAttributeWhere}
+	return model.Where{Arg: a, Value: operand.Value{Value: v}, Operator: enum.LessEquals, Type: enum.OperationAttributeWhere}
 }
@@ -0,0 +1,195 @@
+package window
+
+import (
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+)
+
+// Window RowNumber uses database window function to number the rows of each partition, starting at 1.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name   string
+//		Number int64
+//	}](&db.Animal.Name, window.RowNumber().PartitionBy(&db.Animal.HabitatId).OrderByAsc(&db.Animal.Name))...
+func RowNumber() *window[int64] {
+	return &window[int64]{Type: enum.RowNumberWindow}
+}
+
+// Window Rank uses database window function to rank the rows of each partition, the rows
+// with equal order have the same rank and leave gaps on the next rank.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Rank int64
+//	}](&db.Exam.Name, window.Rank().OrderByDesc(&db.Exam.Result))...
+func Rank() *window[int64] {
+	return &window[int64]{Type: enum.RankWindow}
+}
+
+// Window DenseRank uses database window function to rank the rows of each partition, the rows
+// with equal order have the same rank without gaps on the next rank.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Rank int64
+//	}](&db.Exam.Name, window.DenseRank().OrderByDesc(&db.Exam.Result))...
+func DenseRank() *window[int64] {
+	return &window[int64]{Type: enum.DenseRankWindow}
+}
+
+// Window Lag uses database window function to get the target of the row offset rows before
+// the current row, the value is null on the first rows of the partition.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Day      time.Time
+//		Previous *float64
+//	}](&db.Sale.Day, window.Lag(&db.Sale.Total, 1).OrderByAsc(&db.Sale.Day))...
+func Lag[T any](target *T, offset int) *window[T] {
+	return &window[T]{Field: target, Type: enum.LagWindow, Offset: offset}
+}
+
+// Window Lead uses database window function to get the target of the row offset rows after
+// the current row, the value is null on the last rows of the partition.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Day  time.Time
+//		Next *float64
+//	}](&db.Sale.Day, window.Lead(&db.Sale.Total, 1).OrderByAsc(&db.Sale.Day))...
+func Lead[T any](target *T, offset int) *window[T] {
+	return &window[T]{Field: target, Type: enum.LeadWindow, Offset: offset}
+}
+
+// Window Count uses database aggregate to count the target over the rows of the window.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name    string
+//		Animals int64
+//	}](&db.Animal.Name, window.Count(&db.Animal.Id).PartitionBy(&db.Animal.HabitatId))...
+func Count(t any) *window[int64] {
+	return &window[int64]{Field: t, Type: enum.AggregateWindow, Aggregate: enum.CountAggregate}
+}
+
+// Window Avg uses database aggregate to get a average of the target over the rows of the window.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Avg  float64
+//	}](&db.Exam.Name, window.Avg(&db.Exam.Result).PartitionBy(&db.Exam.Subject))...
+func Avg(t any) *window[float64] {
+	return &window[float64]{Field: t, Type: enum.AggregateWindow, Aggregate: enum.AvgAggregate}
+}
+
+// Window Max uses database aggregate to get the maximum value of the target over the rows of the window.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Max  float64
+//	}](&db.Exam.Name, window.Max(&db.Exam.Result).PartitionBy(&db.Exam.Subject))...
+func Max(t any) *window[float64] {
+	return &window[float64]{Field: t, Type: enum.AggregateWindow, Aggregate: enum.MaxAggregate}
+}
+
+// Window Min uses database aggregate to get the minimum value of the target over the rows of the window.
+//
+// # Example
+//
+//	goe.Select[struct {
+//		Name string
+//		Min  float64
+//	}](&db.Exam.Name, window.Min(&db.Exam.Result).PartitionBy(&db.Exam.Subject))...
+func Min(t any) *window[float64] {
+	return &window[float64]{Field: t, Type: enum.AggregateWindow, Aggregate: enum.MinAggregate}
+}
+
+// Window Sum uses database aggregate to sum the target over the rows of the window,
+// with a order the sum is the running total until the current row.
+//
+// # Example
+//
+//	// running total of the sales by day
+//	goe.Select[struct {
+//		Day   time.Time
+//		Total float64
+//	}](&db.Sale.Day, window.Sum(&db.Sale.Total).OrderByAsc(&db.Sale.Day))...
+func Sum(t any) *window[float64] {
+	return &window[float64]{Field: t, Type: enum.AggregateWindow, Aggregate: enum.SumAggregate}
+}
+
+type window[T any] struct {
+	Field     any
+	Type      enum.WindowType
+	Aggregate enum.AggregateType
+	Offset    int
+	partition []any
+	order     []model.WindowOrder
+}
+
+// PartitionBy splits the rows of the window by the fields, the window function runs on each partition.
+func (w *window[T]) PartitionBy(fields ...any) *window[T] {
+	w.partition = append(w.partition, fields...)
+	return w
+}
+
+// OrderByAsc orders the rows of each partition by the fields in ascending order.
+func (w *window[T]) OrderByAsc(fields ...any) *window[T] {
+	for _, f := range fields {
+		w.order = append(w.order, model.WindowOrder{Field: f})
+	}
+	return w
+}
+
+// OrderByDesc orders the rows of each partition by the fields in descending order.
+func (w *window[T]) OrderByDesc(fields ...any) *window[T] {
+	for _, f := range fields {
+		w.order = append(w.order, model.WindowOrder{Field: f, Desc: true})
+	}
+	return w
+}
+
+func (w window[T]) Attribute(b model.Body) model.Attribute {
+	return model.Attribute{
+		Table:         b.Table,
+		Name:          b.Name,
+		AggregateType: w.Aggregate,
+		Window:        &model.Window{Type: w.Type, Offset: w.Offset},
+	}
+}
+
+// GetField returns the target of the function, the ranking functions without target
+// return the first field of the order or the partition
+func (w window[T]) GetField() any {
+	if w.Field != nil {
+		return w.Field
+	}
+	if len(w.order) != 0 {
+		return w.order[0].Field
+	}
+	if len(w.partition) != 0 {
+		return w.partition[0]
+	}
+	return nil
+}
+
+func (w window[T]) Partition() []any {
+	return w.partition
+}
+
+func (w window[T]) Order() []model.WindowOrder {
+	return w.order
+}
@@ -2,13 +2,17 @@ package goe
 
 import (
 	"context"
+	"fmt"
 	"iter"
 	"maps"
 	"math"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/internal/operand"
 	"github.com/go-goe/goe/model"
 	"github.com/go-goe/goe/query/aggregate"
 	"github.com/go-goe/goe/query/function"
@@ -16,9 +20,11 @@ import (
 )
 
 type stateSelect[T any] struct {
-	conn    model.Connection
-	builder builder
-	ctx     context.Context
+	conn         model.Connection
+	builder      builder
+	ctx          context.Context
+	includes     []relation
+	joinIncludes []joinInclude
 	argsSelect
 }
 
@@ -85,7 +91,7 @@ func (f find[T]) OnTransaction(tx model.Transaction) find[T] {
 // and ignores the rest
 func (f find[T]) ByValue(value T) (*T, error) {
 	pks, valuesPks, skip := getNonZeroFields(getArgs{
-		addrMap:   addrMap.mapField,
+		addrMap:   loadFields(),
 		tableArgs: f.sSelect.tableArgs,
 		value:     value})
 
@@ -136,6 +142,13 @@ func (f find[T]) ByValue(value T) (*T, error) {
 //		//handler rows
 //		result = append(result, row)
 //	}
+//
+//	// a pointer to a table fills the struct field of the same type, nil if the left join have no match
+//	rows, err = goe.Select[struct {
+//			Animal  Animal
+//			Habitat *Habitat
+//		}](db.Animal, db.Habitat).
+//		LeftJoin(&db.Animal.HabitatID, &db.Habitat.ID).AsSlice()
 func Select[T any](args ...any) stateSelect[T] {
 	return SelectContext[T](context.Background(), args...)
 }
@@ -152,14 +165,14 @@ func SelectContext[T any](ctx context.Context, args ...any) stateSelect[T] {
 func (s stateSelect[T]) Where(o model.Where) stateSelect[T] {
 	s.builder.query.WhereOperations = nil
 	s.builder.tables = maps.Clone(s.builder.tables)
-	helperWhere(&s.builder, addrMap.mapField, &o)
+	helperWhere(&s.builder, loadQueryFields(s.ctx), &o)
 	s.builder.query.Where = &o
 	return s
 }
 
 // Filter creates a where on non-zero values.
 func (s stateSelect[T]) Filter(filter model.Where) stateSelect[T] {
-	s.builder.filter = helperFilter(&s.builder, addrMap.mapField, &filter)
+	s.builder.filter = helperFilter(&s.builder, loadQueryFields(s.ctx), &filter)
 	return s
 }
 
@@ -167,7 +180,7 @@ func (s stateSelect[T]) Filter(filter model.Where) stateSelect[T] {
 // using the ToUpper function to ensure all values is matched.
 func (s stateSelect[T]) Match(value T) stateSelect[T] {
 	args, values, skip := getNonZeroFields(getArgs{
-		addrMap:   addrMap.mapField,
+		addrMap:   loadQueryFields(s.ctx),
 		tableArgs: s.tableArgs,
 		value:     value})
 
@@ -193,7 +206,11 @@ func (s stateSelect[T]) Skip(i int) stateSelect[T] {
 // OrderByAsc makes a ordained by args ascending query
 func (s stateSelect[T]) OrderByAsc(args ...any) stateSelect[T] {
 	for _, arg := range args {
-		if a, ok := getAttribute(arg, addrMap.mapField); ok {
+		if p := s.builder.selectedPosition(arg); p != 0 {
+			s.builder.query.OrderBy = append(s.builder.query.OrderBy, model.OrderBy{Position: p})
+			continue
+		}
+		if a, ok := getAttribute(arg, loadQueryFields(s.ctx)); ok {
 			s.builder.query.OrderBy = append(s.builder.query.OrderBy, model.OrderBy{Attribute: a})
 		}
 	}
@@ -203,18 +220,82 @@ func (s stateSelect[T]) OrderByAsc(args ...any) stateSelect[T] {
 // OrderByDesc makes a ordained by args descending query
 func (s stateSelect[T]) OrderByDesc(args ...any) stateSelect[T] {
 	for _, arg := range args {
-		if a, ok := getAttribute(arg, addrMap.mapField); ok {
+		if p := s.builder.selectedPosition(arg); p != 0 {
+			s.builder.query.OrderBy = append(s.builder.query.OrderBy, model.OrderBy{Position: p, Desc: true})
+			continue
+		}
+		if a, ok := getAttribute(arg, loadQueryFields(s.ctx)); ok {
 			s.builder.query.OrderBy = append(s.builder.query.OrderBy, model.OrderBy{Attribute: a, Desc: true})
 		}
 	}
 	return s
 }
 
+// Having receives [model.Where] as having operations from where sub package,
+// the left side of the operations can be a aggregate from aggregate sub package.
+//
+// # Example
+//
+//	// habitats with more than 10 animals
+//	count := aggregate.Count(&db.Animal.Id)
+//	goe.Select[struct {
+//		Name  string
+//		Count int64
+//	}](&db.Habitat.Name, count).
+//		Join(&db.Animal.HabitatId, &db.Habitat.Id).
+//		GroupBy(&db.Habitat.Name).
+//		Having(where.Greater(count, aggregate.Argument(count, 10))).AsSlice()
+func (s stateSelect[T]) Having(o model.Where) stateSelect[T] {
+	// the having arguments are appended after the where arguments on build
+	having := builder{tables: maps.Clone(s.builder.tables)}
+	helperWhere(&having, loadQueryFields(s.ctx), &o)
+	s.builder.tables = having.tables
+	s.builder.query.Tables = append(slices.Clip(s.builder.query.Tables), having.query.Tables...)
+	s.builder.query.Having = &o
+	s.builder.havingArguments = having.query.Arguments
+	s.builder.withs = append(slices.Clip(s.builder.withs), having.withs...)
+	return s
+}
+
+// Distinct removes the duplicated rows of the result
+//
+// # Example
+//
+//	// select distinct animals.name from animals
+//	names, err = goe.Select[struct{ Name string }](&db.Animal.Name).Distinct().AsSlice()
+func (s stateSelect[T]) Distinct() stateSelect[T] {
+	s.builder.query.Distinct = true
+	return s
+}
+
+// DistinctOn keeps only the first row of each group of rows with equal args,
+// the first row is defined by the order that needs to start with args.
+// DistinctOn is supported only by dialects with DISTINCT ON (e.g. PostgreSQL),
+// on the others the query returns a error wrapping [ErrUnsupported].
+//
+// # Example
+//
+//	// the animal with the lowest id of each habitat
+//	animals, err = goe.List(db.Animal).DistinctOn(&db.Animal.HabitatId).OrderByAsc(&db.Animal.HabitatId, &db.Animal.Id).AsSlice()
+func (s stateSelect[T]) DistinctOn(args ...any) stateSelect[T] {
+	s.builder.query.DistinctOn = slices.Clip(s.builder.query.DistinctOn)
+	for _, arg := range args {
+		if a, ok := getAttribute(arg, loadQueryFields(s.ctx)); ok {
+			s.builder.query.DistinctOn = append(s.builder.query.DistinctOn, a)
+		}
+	}
+	return s
+}
+
 // GroupBy makes a group by args
 func (s stateSelect[T]) GroupBy(args ...any) stateSelect[T] {
 	s.builder.query.GroupBy = make([]model.GroupBy, len(args))
 	for i := range args {
-		if a, ok := getAttribute(args[i], addrMap.mapField); ok {
+		if p := s.builder.selectedPosition(args[i]); p != 0 {
+			s.builder.query.GroupBy[i].Position = p
+			continue
+		}
+		if a, ok := getAttribute(args[i], loadQueryFields(s.ctx)); ok {
 			s.builder.query.GroupBy[i].Attribute = a
 		}
 	}
@@ -222,32 +303,183 @@ func (s stateSelect[T]) GroupBy(args ...any) stateSelect[T] {
 }
 
 func (s stateSelect[T]) Join(left, right any) stateSelect[T] {
-	s.builder.buildSelectJoins(enum.Join, getArgsJoin(addrMap.mapField, left, right))
+	s.builder.buildSelectJoins(enum.Join, getArgsJoin(loadQueryFields(s.ctx), left, right))
 	return s
 }
 
 func (s stateSelect[T]) LeftJoin(left, right any) stateSelect[T] {
-	s.builder.buildSelectJoins(enum.LeftJoin, getArgsJoin(addrMap.mapField, left, right))
+	s.builder.buildSelectJoins(enum.LeftJoin, getArgsJoin(loadQueryFields(s.ctx), left, right))
 	return s
 }
 
 func (s stateSelect[T]) RightJoin(left, right any) stateSelect[T] {
-	s.builder.buildSelectJoins(enum.RightJoin, getArgsJoin(addrMap.mapField, left, right))
+	s.builder.buildSelectJoins(enum.RightJoin, getArgsJoin(loadQueryFields(s.ctx), left, right))
+	return s
+}
+
+// FullJoin makes a full outer join, keeping the rows of both sides without match
+func (s stateSelect[T]) FullJoin(left, right any) stateSelect[T] {
+	s.builder.buildSelectJoins(enum.FullJoin, getArgsJoin(loadQueryFields(s.ctx), left, right))
+	return s
+}
+
+// CrossJoin joins all the rows of table with all the rows of the query
+//
+// # Example
+//
+//	// all the combinations of animals and foods
+//	goe.Select[struct{ Animal, Food string }](&db.Animal.Name, &db.Food.Name).CrossJoin(db.Food).AsSlice()
+func (s stateSelect[T]) CrossJoin(table any) stateSelect[T] {
+	s.builder.buildSelectJoinsOn(enum.CrossJoin, getArgJoinTable(loadQueryFields(s.ctx), table), nil)
+	return s
+}
+
+// JoinOn joins table with the condition on, any [model.Where] from where sub package can be used as condition.
+//
+// # Example
+//
+//	// join habitats on (habitats.id = animals.habitat_id and habitats.name <> $1)
+//	goe.List(db.Animal).
+//		JoinOn(db.Habitat, where.And(
+//			where.EqualsArg[uuid.UUID](&db.Habitat.Id, &db.Animal.HabitatId),
+//			where.NotEquals(&db.Habitat.Name, "City"),
+//		)).AsSlice()
+func (s stateSelect[T]) JoinOn(table any, on model.Where) stateSelect[T] {
+	s.builder.buildSelectJoinsOn(enum.Join, getArgJoinTable(loadQueryFields(s.ctx), table), getArgJoinOn(loadQueryFields(s.ctx), on))
+	return s
+}
+
+// LeftJoinOn makes a left join of table with the condition on, as [stateSelect.JoinOn]
+func (s stateSelect[T]) LeftJoinOn(table any, on model.Where) stateSelect[T] {
+	s.builder.buildSelectJoinsOn(enum.LeftJoin, getArgJoinTable(loadQueryFields(s.ctx), table), getArgJoinOn(loadQueryFields(s.ctx), on))
+	return s
+}
+
+// RightJoinOn makes a right join of table with the condition on, as [stateSelect.JoinOn]
+func (s stateSelect[T]) RightJoinOn(table any, on model.Where) stateSelect[T] {
+	s.builder.buildSelectJoinsOn(enum.RightJoin, getArgJoinTable(loadQueryFields(s.ctx), table), getArgJoinOn(loadQueryFields(s.ctx), on))
+	return s
+}
+
+// FullJoinOn makes a full outer join of table with the condition on, as [stateSelect.JoinOn]
+func (s stateSelect[T]) FullJoinOn(table any, on model.Where) stateSelect[T] {
+	s.builder.buildSelectJoinsOn(enum.FullJoin, getArgJoinTable(loadQueryFields(s.ctx), table), getArgJoinOn(loadQueryFields(s.ctx), on))
+	return s
+}
+
+// Include loads the relations with one extra query per relation.
+// The has-many relations of the slice fields are matched by the foreign key referencing the parent primary key,
+// the belongs-to and one to one relations of a companion field (e.g. Habitat *Habitat next to HabitatId)
+// are matched by the primary key referenced by the foreign key.
+//
+// A nested relation is included after the relation of the parent,
+// Include is loaded by [stateSelect.AsSlice], [stateSelect.AsPagination] and [stateSelect.AsCursor],
+// [stateSelect.Rows] panics if there is a relation to include.
+//
+// # Example
+//
+//	// select habitats; select animals where habitat_id in (...)
+//	habitats, err = goe.List(db.Habitat).Include(&db.Habitat.Animals).AsSlice()
+//
+//	// select animals; select habitats where id in (...)
+//	animals, err = goe.List(db.Animal).Include(&db.Animal.Habitat).AsSlice()
+//
+//	// nested relations
+//	weathers, err = goe.List(db.Weather).Include(&db.Weather.Habitats, &db.Habitat.Animals).AsSlice()
+func (s stateSelect[T]) Include(relations ...any) stateSelect[T] {
+	s.includes = getArgsInclude(s.builder.fieldsSelect[0].getDb(), reflect.TypeFor[T](), slices.Clip(s.includes), relations...)
+	return s
+}
+
+// IncludeJoin loads the belongs-to and one to one relations of the companion fields on the same query,
+// the referenced table is selected with a left join and a companion without match stays zero (nil if pointer).
+//
+// IncludeJoin accepts only relations of the selected struct, for nested relations use [stateSelect.Include].
+//
+// # Example
+//
+//	// select animals.*, habitats.* from animals left join habitats on (animals.habitat_id = habitats.id)
+//	animals, err = goe.List(db.Animal).IncludeJoin(&db.Animal.Habitat).AsSlice()
+func (s stateSelect[T]) IncludeJoin(relations ...any) stateSelect[T] {
+	for _, arg := range relations {
+		ji, join, fields := getArgJoinInclude(s.builder.fieldsSelect[0].getDb(), reflect.TypeFor[T](), arg)
+		s.builder.buildSelectJoins(enum.LeftJoin, join)
+
+		i := len(s.builder.fieldsSelect)
+		s.builder.query.Attributes = append(slices.Clip(s.builder.query.Attributes), make([]model.Attribute, len(fields))...)
+		s.builder.fieldsSelect = slices.Clip(s.builder.fieldsSelect)
+		for _, f := range fields {
+			s.builder.fieldsSelect = append(s.builder.fieldsSelect, f)
+			f.buildAttributeSelect(s.builder.query.Attributes, i)
+			i++
+		}
+		s.joinIncludes = append(slices.Clip(s.joinIncludes), ji)
+	}
 	return s
 }
 
 // AsSlice return all the rows as a slice.
 func (s stateSelect[T]) AsSlice() ([]T, error) {
 	rows := make([]T, 0, s.builder.query.Limit)
-	for row, err := range s.Rows() {
+	for row, err := range s.rows() {
 		if err != nil {
 			return nil, err
 		}
 		rows = append(rows, row)
 	}
+
+	if len(s.includes) != 0 && len(rows) != 0 {
+		if s.conn == nil {
+			s.conn = s.builder.fieldsSelect[0].getDb().driver.NewConnection()
+		}
+		if err := loadIncludes(s.ctx, s.conn, reflect.ValueOf(rows), s.builder.query.ForUpdate, s.includes); err != nil {
+			return nil, err
+		}
+	}
 	return rows, nil
 }
 
+// Union combines the rows of the queries removing the duplicated rows,
+// the queries needs to select the same number of columns.
+//
+// OrderBy, Take and Skip of the combined query apply to all the rows,
+// the order by of the second query is ignored.
+//
+// # Example
+//
+//	// SELECT orders.id FROM orders UNION SELECT archived_orders.id FROM archived_orders ORDER BY id
+//	ids, err := goe.Union(
+//		goe.Select[struct{ Id int }](&db.Order.Id),
+//		goe.Select[struct{ Id int }](&db.ArchivedOrder.Id),
+//	).OrderByAsc(&db.Order.Id).AsSlice()
+func Union[T any](first, second stateSelect[T]) stateSelect[T] {
+	return compound(enum.UnionCompound, first, second)
+}
+
+// UnionAll combines the rows of the queries keeping the duplicated rows, as [Union].
+func UnionAll[T any](first, second stateSelect[T]) stateSelect[T] {
+	return compound(enum.UnionAllCompound, first, second)
+}
+
+// Intersect returns the rows of the first query that are also returned by the second, as [Union].
+func Intersect[T any](first, second stateSelect[T]) stateSelect[T] {
+	return compound(enum.IntersectCompound, first, second)
+}
+
+// Except returns the rows of the first query that are not returned by the second, as [Union].
+func Except[T any](first, second stateSelect[T]) stateSelect[T] {
+	return compound(enum.ExceptCompound, first, second)
+}
+
+func compound[T any](compoundType enum.CompoundType, first, second stateSelect[T]) stateSelect[T] {
+	if len(first.builder.fieldsSelect) != len(second.builder.fieldsSelect) {
+		panic("goe: invalid compound query. try selecting the same number of columns on both queries")
+	}
+	second.builder.query.OrderBy, second.builder.query.Limit, second.builder.query.Offset = nil, 0, 0
+	first.builder.compounds = append(slices.Clip(first.builder.compounds), model.Compound{Type: compoundType, Query: second.AsQuery()})
+	return first
+}
+
 // AsQuery return a [model.Query] for use inside a [where.In].
 func (s stateSelect[T]) AsQuery() model.Query {
 	s.builder.buildSqlSelect()
@@ -283,29 +515,9 @@ func (s stateSelect[T]) AsPagination(page, size int) (*Pagination[T], error) {
 		page = 1
 	}
 
-	var err error
-	stateCount := Select[struct{ Count int64 }](aggregate.Count(s.tableArgs[0]))
-
-	// copy joins
-	stateCount.builder.joins = s.builder.joins
-	stateCount.builder.joinsArgs = s.builder.joinsArgs
-
-	// copy operations
-	stateCount.builder.query.Arguments = s.builder.query.Arguments
-	stateCount.builder.whereArguments = s.builder.whereArguments
-	stateCount.builder.filter = s.builder.filter
-	stateCount.builder.query.Where = s.builder.query.Where
-
-	// copy connection/transaction
-	stateCount.conn = s.conn
-
-	var count int64
-	for row, err := range stateCount.Rows() {
-		if err != nil {
-			return nil, err
-		}
-		count = row.Count
-		break
+	count, err := s.count()
+	if err != nil {
+		return nil, err
 	}
 
 	s.builder.query.Offset = size * (page - 1)
@@ -351,6 +563,54 @@ func (s stateSelect[T]) AsPagination(page, size int) (*Pagination[T], error) {
 	return p, nil
 }
 
+// count returns the number of rows of the query without limit and offset,
+// a distinct or grouped query is counted over a subquery
+func (s stateSelect[T]) count() (int64, error) {
+	if !s.builder.query.Distinct && len(s.builder.query.DistinctOn) == 0 && len(s.builder.query.GroupBy) == 0 && s.builder.query.Having == nil && len(s.builder.compounds) == 0 {
+		stateCount := SelectContext[struct{ Count int64 }](s.ctx, aggregate.Count(s.tableArgs[0]))
+
+		// copy joins
+		stateCount.builder.joins = s.builder.joins
+		stateCount.builder.joinsArgs = s.builder.joinsArgs
+		stateCount.builder.joinsOn = s.builder.joinsOn
+		stateCount.builder.withs = s.builder.withs
+
+		// copy operations
+		stateCount.builder.query.Arguments = s.builder.query.Arguments
+		stateCount.builder.whereArguments = s.builder.whereArguments
+		stateCount.builder.filter = s.builder.filter
+		stateCount.builder.query.Where = s.builder.query.Where
+
+		// copy connection/transaction
+		stateCount.conn = s.conn
+
+		for row, err := range stateCount.Rows() {
+			return row.Count, err
+		}
+		return 0, nil
+	}
+
+	s.builder.query.Limit, s.builder.query.Offset, s.builder.query.OrderBy = 0, 0, nil
+	s.builder.buildSqlSelect()
+
+	query := model.Query{
+		Type:       enum.SelectQuery,
+		Attributes: []model.Attribute{{Name: "*", AggregateType: enum.CountAggregate}},
+		FromQuery:  &s.builder.query,
+		Arguments:  s.builder.query.Arguments,
+	}
+	query.Header.ModelBuild = s.builder.query.Header.ModelBuild
+
+	db := s.builder.fieldsSelect[0].getDb()
+	if s.conn == nil {
+		s.conn = db.driver.NewConnection()
+	}
+	for row, err := range handlerResult[struct{ Count int64 }](s.ctx, s.conn, query, 1, nil, nil, db) {
+		return row.Count, err
+	}
+	return 0, nil
+}
+
 // OnTransaction sets a transaction on the query.
 //
 // # Example
@@ -379,19 +639,34 @@ func (s stateSelect[T]) OnTransaction(tx model.Transaction) stateSelect[T] {
 }
 
 // Rows return a iterator on rows.
+//
+// The relations of Include are not loaded on Rows, as the rows are not known until the end
+// and the connection is busy with the iteration, use [stateSelect.AsSlice] instead.
 func (s stateSelect[T]) Rows() iter.Seq2[T, error] {
+	if len(s.includes) != 0 {
+		panic("goe: invalid include. try loading the relations with AsSlice, AsPagination or AsCursor")
+	}
+	return s.rows()
+}
+
+// rows returns the iterator on rows without the relations of Include, loaded by AsSlice after the iteration
+func (s stateSelect[T]) rows() iter.Seq2[T, error] {
 	s.builder.buildSqlSelect()
 
-	driver := s.builder.fieldsSelect[0].getDb().driver
+	db := s.builder.fieldsSelect[0].getDb()
 	if s.conn == nil {
-		s.conn = driver.NewConnection()
+		s.conn = db.driver.NewConnection()
 	}
 
-	return handlerResult[T](s.ctx, s.conn, s.builder.query, len(s.builder.fieldsSelect), driver.GetDatabaseConfig())
+	numFields := len(s.builder.fieldsSelect)
+	for _, ji := range s.joinIncludes {
+		numFields -= len(ji.fieldIndexes)
+	}
+	return handlerResult[T](s.ctx, s.conn, s.builder.query, numFields, s.nested, s.joinIncludes, db)
 }
 
-func createSelectState[T any](ctx context.Context, getArgs func(args ...any) argsSelect, args ...any) stateSelect[T] {
-	s := stateSelect[T]{builder: createBuilder(enum.SelectQuery), argsSelect: getArgs(args...), ctx: ctx}
+func createSelectState[T any](ctx context.Context, getArgs func(addrMap databases, args ...any) argsSelect, args ...any) stateSelect[T] {
+	s := stateSelect[T]{builder: createBuilder(enum.SelectQuery), argsSelect: getArgs(loadQueryFields(ctx), args...), ctx: ctx}
 	s.builder.fieldsSelect = s.fields
 	s.builder.buildSelect()
 	return s
@@ -422,7 +697,7 @@ func ListContext[T any](ctx context.Context, table *T) stateSelect[T] {
 }
 
 type getArgs struct {
-	addrMap   map[uintptr]field
+	addrMap   databases
 	value     any
 	tableArgs []any
 }
@@ -431,10 +706,11 @@ func getNonZeroFields(a getArgs) ([]any, []any, bool) {
 	args, values := make([]any, 0), make([]any, 0)
 
 	valueOf := reflect.ValueOf(a.value)
-	for i := 0; i < valueOf.NumField(); i++ {
-		if !valueOf.Field(i).IsZero() {
-			args = append(args, a.tableArgs[i])
-			values = append(values, valueOf.Field(i).Interface())
+	for _, arg := range a.tableArgs {
+		fieldOf := valueOf.FieldByIndex(a.addrMap.field(uintptr(reflect.ValueOf(arg).UnsafePointer())).getFieldIndex())
+		if !fieldOf.IsZero() {
+			args = append(args, arg)
+			values = append(values, fieldOf.Interface())
 		}
 	}
 
@@ -489,29 +765,168 @@ func equalsOrLike(f any, a any) model.Where {
 type argsSelect struct {
 	fields    []fieldSelect
 	tableArgs []any
+	nested    []nestedTable
 }
 
-func createFunction(field field, a any) fieldSelect {
-	if f, ok := a.(model.FunctionType); ok {
-		return functionResult{
-			tableName:     field.table(),
-			schemaName:    field.schema(),
-			tableId:       field.getTableId(),
-			db:            field.getDb(),
-			attributeName: field.getAttributeName(),
-			functionType:  f.GetType()}
+// createFunction maps the function f, the result of a function without columns
+// has no table until is set by setFunctionTables
+func createFunction(addrMap databases, f model.FunctionType) functionResult {
+	function, field := buildFunction(addrMap, f)
+	var source model.FunctionType
+	if len(functionArguments(nil, function)) != 0 {
+		source = f
+	}
+	if field == nil {
+		return functionResult{function: function, source: source}
+	}
+	return functionResult{
+		tableName:     field.table(),
+		sourceName:    field.getTable().Name,
+		schemaName:    field.schema(),
+		tableId:       field.getTableId(),
+		db:            field.getDb(),
+		with:          withOf(field),
+		attributeName: field.getAttributeName(),
+		function:      function,
+		source:        source}
+}
+
+// setFunctionTables sets the table of the functions and cases without columns (e.g. now) as the table of the first field with a table
+func setFunctionTables(fields []fieldSelect) {
+	i := slices.IndexFunc(fields, func(f fieldSelect) bool { return f.getDb() != nil })
+	if i == -1 {
+		panic("goe: invalid argument. try sending a pointer to a database mapped argument")
 	}
+	for j, f := range fields {
+		if fr, ok := f.(functionResult); ok && fr.db == nil {
+			fr.tableName, fr.schemaName, fr.tableId, fr.db = fields[i].table(), fields[i].schema(), fields[i].getTableId(), fields[i].getDb()
+			fr.sourceName, fr.with = fields[i].getTable().Name, withOf(fields[i])
+			fields[j] = fr
+		}
+		if cr, ok := f.(caseResult); ok && cr.db == nil {
+			cr.tableName, cr.schemaName, cr.tableId, cr.db = fields[i].table(), fields[i].schema(), fields[i].getTableId(), fields[i].getDb()
+			cr.sourceName, cr.with = fields[i].getTable().Name, withOf(fields[i])
+			fields[j] = cr
+		}
+	}
+}
 
+// buildFunction maps the arguments of f as attributes, values and nested functions,
+// returns the function and the first column used by the function, nil if the function has no columns
+func buildFunction(addrMap databases, f model.FunctionType) (*model.Function, field) {
+	arguments := argumentsOf(f)
+	function := &model.Function{Type: f.GetType(), Arguments: make([]model.FunctionArgument, 0, len(arguments))}
+	var column field
+	for _, arg := range arguments {
+		switch a := arg.(type) {
+		case model.FunctionArgument:
+			if function.Type == enum.CastFunction && a.Keyword != "" && !castType.MatchString(a.Keyword) {
+				panic(fmt.Sprintf("goe: invalid cast type %q. try using a type name with a optional size, e.g. varchar(20)", a.Keyword))
+			}
+			if (function.Type == enum.DatePartFunction || function.Type == enum.DateTruncFunction) && a.Keyword != "" && !slices.Contains(dateParts, a.Keyword) {
+				panic(fmt.Sprintf("goe: invalid date part %q. try using one of %v", a.Keyword, dateParts))
+			}
+			function.Arguments = append(function.Arguments, a)
+		case model.FunctionType:
+			nested, c := buildFunction(addrMap, a)
+			function.Arguments = append(function.Arguments, model.FunctionArgument{Function: nested})
+			if column == nil {
+				column = c
+			}
+		default:
+			valueOf := reflect.ValueOf(arg)
+			if valueOf.Kind() != reflect.Pointer {
+				function.Arguments = append(function.Arguments, model.FunctionArgument{Value: arg})
+				continue
+			}
+			c := addrMap.field(uintptr(valueOf.UnsafePointer()))
+			if c == nil {
+				panic("goe: invalid function. try sending a pointer to a database mapped field")
+			}
+			function.Arguments = append(function.Arguments, model.FunctionArgument{Attribute: &model.Attribute{Table: c.table(), Name: c.getAttributeName()}})
+			if column == nil {
+				column = c
+			}
+		}
+	}
+	return function, column
+}
+
+// argumentsOf returns the arguments of f, the field of the functions without [model.FunctionArguments]
+func argumentsOf(f model.FunctionType) []any {
+	if a, ok := f.(model.FunctionArguments); ok {
+		return a.GetArguments()
+	}
+	if a, ok := f.(model.Attributer); ok && a.GetField() != nil {
+		return []any{a.GetField()}
+	}
 	return nil
 }
 
+// dateParts are the parts of a date accepted by date part and date trunc
+var dateParts = []string{"year", "month", "day", "hour", "minute", "second"}
+
+// castType matches the data types of a cast, a name of one or more words with a optional size and precision
+var castType = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*( [A-Za-z][A-Za-z0-9_]*)*( ?\([0-9]+( ?, ?[0-9]+)?\))?$`)
+
+// functionType returns the type of f to set on the deprecated Attribute.FunctionType,
+// zero if f is not a function of a single column
+func functionType(f *model.Function) enum.FunctionType {
+	if f == nil || len(f.Arguments) != 1 || f.Arguments[0].Attribute == nil {
+		return 0
+	}
+	return f.Type
+}
+
+// createWindow maps the target and the fields of the partition and the order of the window function w
+func createWindow(addrMap databases, w model.Windower) fieldSelect {
+	if w.GetField() == nil {
+		panic("goe: invalid window. try sending a partition or a order to the window function")
+	}
+	field := getWindowArg(addrMap, w.GetField())
+	attribute := w.Attribute(model.Body{Table: field.table(), Name: field.getAttributeName()})
+
+	window := *attribute.Window
+	for _, p := range w.Partition() {
+		f := getWindowArg(addrMap, p)
+		window.PartitionBy = append(window.PartitionBy, model.Attribute{Table: f.table(), Name: f.getAttributeName()})
+	}
+	for _, o := range w.Order() {
+		f := getWindowArg(addrMap, o.Field)
+		window.OrderBy = append(window.OrderBy, model.OrderBy{Desc: o.Desc, Attribute: model.Attribute{Table: f.table(), Name: f.getAttributeName()}})
+	}
+
+	return windowResult{
+		tableName:     field.table(),
+		sourceName:    field.getTable().Name,
+		schemaName:    field.schema(),
+		tableId:       field.getTableId(),
+		db:            field.getDb(),
+		with:          withOf(field),
+		attributeName: field.getAttributeName(),
+		aggregateType: attribute.AggregateType,
+		window:        window}
+}
+
+func getWindowArg(addrMap databases, arg any) field {
+	valueOf := reflect.ValueOf(arg)
+	if valueOf.Kind() == reflect.Pointer {
+		if f := addrMap.field(uintptr(valueOf.UnsafePointer())); f != nil {
+			return f
+		}
+	}
+	panic("goe: invalid window. try sending a pointer to a database mapped field")
+}
+
 func createAggregate(field field, a any) fieldSelect {
 	if ag, ok := a.(model.Aggregate); ok {
 		return aggregateResult{
 			tableName:     field.table(),
+			sourceName:    field.getTable().Name,
 			schemaName:    field.schema(),
 			tableId:       field.getTableId(),
 			db:            field.getDb(),
+			with:          withOf(field),
 			attributeName: field.getAttributeName(),
 			aggregateType: ag.Aggregate()}
 	}
@@ -519,7 +934,7 @@ func createAggregate(field field, a any) fieldSelect {
 	return nil
 }
 
-func getArgsJoin(addrMap map[uintptr]field, args ...any) []field {
+func getArgsJoin(addrMap databases, args ...any) []field {
 	fields := make([]field, 2)
 	var ptr uintptr
 	var valueOf reflect.Value
@@ -528,7 +943,7 @@ func getArgsJoin(addrMap map[uintptr]field, args ...any) []field {
 		valueOf = reflect.ValueOf(args[i])
 		if valueOf.Kind() == reflect.Pointer {
 			ptr = uintptr(valueOf.UnsafePointer())
-			f = addrMap[ptr]
+			f = addrMap.field(ptr)
 			if f != nil {
 				fields[i] = f
 			}
@@ -543,20 +958,59 @@ func getArgsJoin(addrMap map[uintptr]field, args ...any) []field {
 	return fields
 }
 
-func getArgFunction(arg any, addrMap map[uintptr]field, operation *model.Where) field {
+// getArgJoinTable returns a field of the table, used to join the table on JoinOn and CrossJoin
+func getArgJoinTable(addrMap databases, table any) field {
+	valueOf := reflect.ValueOf(table)
+	if valueOf.Kind() == reflect.Pointer && valueOf.Elem().Kind() == reflect.Struct {
+		for _, fieldOf := range fieldsOf(valueOf.Elem()) {
+			if f := addrMap.field(uintptr(fieldOf.Addr().UnsafePointer())); f != nil {
+				return f
+			}
+		}
+	}
+	panic("goe: invalid join. try sending a pointer to a table of the database")
+}
+
+// getArgJoinOn resolves the condition of a join, the tables of the condition are not added to the query
+func getArgJoinOn(addrMap databases, on model.Where) *joinOn {
+	b := builder{tables: make(map[int]bool)}
+	helperWhere(&b, addrMap, &on)
+	return &joinOn{on: &on, arguments: b.query.Arguments}
+}
+
+func getArgFunction(arg any, addrMap databases, operation *model.Where) field {
 	value := reflect.ValueOf(arg)
 	if value.IsNil() {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
 
-	if function, ok := value.Elem().Interface().(model.Attributer); ok {
-		operation.Attribute.FunctionType = function.Attribute(model.Body{}).FunctionType
-		return getArg(function.GetField(), addrMap, nil)
+	if e, ok := value.Interface().(model.Expressioner); ok {
+		expression, column := buildExpression(addrMap, e)
+		if column == nil {
+			panic("goe: invalid expression. try using a expression of a database mapped field")
+		}
+		operation.Attribute.Expression = expression
+		return column
+	}
+
+	if f, ok := value.Interface().(model.FunctionType); ok {
+		function, column := buildFunction(addrMap, f)
+		if column == nil {
+			panic("goe: invalid function. try using a function of a database mapped field")
+		}
+		operation.Attribute.Function, operation.Attribute.FunctionType = function, functionType(function)
+		return column
+	}
+
+	if aggregate, ok := value.Elem().Interface().(model.Attributer); ok {
+		attribute := aggregate.Attribute(model.Body{})
+		operation.Attribute.AggregateType = attribute.AggregateType
+		return getArg(aggregate.GetField(), addrMap, nil)
 	}
 	return getArg(arg, addrMap, nil)
 }
 
-func getArg(arg any, addrMap map[uintptr]field, operation *model.Where) field {
+func getArg(arg any, addrMap databases, operation *model.Where) field {
 	v := reflect.ValueOf(arg)
 	if v.Kind() != reflect.Pointer {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
@@ -566,16 +1020,15 @@ func getArg(arg any, addrMap map[uintptr]field, operation *model.Where) field {
 		return getArgFunction(arg, addrMap, operation)
 	}
 
-	addr := uintptr(v.UnsafePointer())
-	if addrMap[addr] != nil {
-		return addrMap[addr]
+	if f := addrMap.field(uintptr(v.UnsafePointer())); f != nil {
+		return f
 	}
 	// any as pointer, used on save, find, remove and list
 	return getAnyArg(v, addrMap)
 }
 
 // used only inside getArg
-func getAnyArg(value reflect.Value, addrMap map[uintptr]field) field {
+func getAnyArg(value reflect.Value, addrMap databases) field {
 	if value.IsNil() {
 		return nil
 	}
@@ -585,26 +1038,45 @@ func getAnyArg(value reflect.Value, addrMap map[uintptr]field) field {
 		return nil
 	}
 
-	addr := uintptr(value.UnsafePointer())
-	if addrMap[addr] != nil {
-		return addrMap[addr]
-	}
-	return nil
+	return addrMap.field(uintptr(value.UnsafePointer()))
 }
 
-func getAttribute(arg any, addrMap map[uintptr]field) (model.Attribute, bool) {
+func getAttribute(arg any, addrMap databases) (model.Attribute, bool) {
 	v := reflect.ValueOf(arg)
 	if v.Kind() != reflect.Pointer {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
 
-	f := addrMap[uintptr(v.UnsafePointer())]
+	f := addrMap.field(uintptr(v.UnsafePointer()))
 	if f != nil {
 		return model.Attribute{Table: f.table(), Name: f.getAttributeName()}, true
 	}
 
+	if fn, ok := v.Interface().(model.FunctionType); ok {
+		function, column := buildFunction(addrMap, fn)
+		if len(functionArguments(nil, function)) != 0 {
+			panic("goe: invalid function. try selecting the function to order or group by it")
+		}
+		if column == nil {
+			return model.Attribute{Function: function}, true
+		}
+		return model.Attribute{Table: column.table(), Name: column.getAttributeName(), Function: function, FunctionType: functionType(function)}, true
+	}
+
+	if e, ok := v.Interface().(model.Expressioner); ok {
+		expression, column := buildExpression(addrMap, e)
+		if column == nil || len(expressionArguments(nil, expression)) != 0 {
+			panic("goe: invalid expression. try selecting the expression to order or group by it")
+		}
+		return model.Attribute{Table: column.table(), Name: column.getAttributeName(), Expression: expression}, true
+	}
+
+	if _, ok := v.Interface().(model.Caser); ok {
+		panic("goe: invalid case. try selecting the case to order or group by it")
+	}
+
 	if a, ok := v.Elem().Interface().(model.Attributer); ok {
-		f = addrMap[uintptr(reflect.ValueOf(a.GetField()).UnsafePointer())]
+		f = addrMap.field(uintptr(reflect.ValueOf(a.GetField()).UnsafePointer()))
 		if f != nil {
 			return a.Attribute(model.Body{
 				Table: f.table(),
@@ -616,20 +1088,26 @@ func getAttribute(arg any, addrMap map[uintptr]field) (model.Attribute, bool) {
 	return model.Attribute{}, false
 }
 
-func helperWhere(builder *builder, addrMap map[uintptr]field, br *model.Where) {
+func helperWhere(builder *builder, addrMap databases, br *model.Where) {
 	switch br.Type {
-	case enum.OperationWhere, enum.OperationInWhere:
+	case enum.OperationWhere, enum.OperationInWhere, enum.OperationBetweenWhere:
 		a := getArg(br.Arg, addrMap, br)
-		br.Table = model.Table{Schema: a.schema(), Name: a.table()}
+		br.Table = a.getTable()
 		br.TableId = a.getTableId()
 		br.Attribute.Name = a.getAttributeName()
 		br.Attribute.Table = a.table()
+		emulateILike(br, a.getDb())
+		appendAttribute(builder, br.Attribute)
 
 		if br.Type == enum.OperationWhere {
 			builder.query.Arguments = append(builder.query.Arguments, br.Value.GetValue())
 			builder.whereArguments++
 		}
 
+		if br.Type == enum.OperationBetweenWhere {
+			appendBetween(builder, br)
+		}
+
 		if br.Type == enum.OperationInWhere {
 			valueOf := reflect.ValueOf(br.Value.GetValue())
 			switch valueOf.Kind() {
@@ -648,32 +1126,42 @@ func helperWhere(builder *builder, addrMap map[uintptr]field, br *model.Where) {
 			default:
 				if modelQuery, ok := valueOf.Interface().(model.Query); ok {
 					br.QueryIn = &modelQuery
+					if supports(a.getDb().driver, enum.SubqueryArgumentsFeature) {
+						mergeSubquery(builder, br, modelQuery)
+					}
 				}
 			}
 		}
 
+	case enum.OperationExistsWhere:
+		if modelQuery, ok := br.Value.GetValue().(model.Query); ok {
+			mergeSubquery(builder, br, modelQuery)
+		}
+
 	case enum.OperationAttributeWhere:
 		a, b := getArg(br.Arg, addrMap, nil), getArg(br.Value.GetValue(), addrMap, nil)
-		br.Table = model.Table{Schema: a.schema(), Name: a.table()}
+		br.Table = a.getTable()
 		br.TableId = a.getTableId()
 		br.Attribute.Name = a.getAttributeName()
 		br.Attribute.Table = a.table()
 		if !builder.tables[br.TableId] {
 			builder.tables[br.TableId] = true
 			builder.query.Tables = append(builder.query.Tables, br.Table)
+			builder.addWith(a)
 		}
 
 		br.AttributeValue.Name = b.getAttributeName()
 		br.AttributeValue.Table = b.table()
-		br.AttributeValueTable = model.Table{Schema: b.schema(), Name: b.table()}
+		br.AttributeValueTable = b.getTable()
 		br.AttributeTableId = b.getTableId()
 		if !builder.tables[br.AttributeTableId] {
 			builder.tables[br.AttributeTableId] = true
 			builder.query.Tables = append(builder.query.Tables, br.AttributeValueTable)
+			builder.addWith(b)
 		}
 	case enum.OperationIsWhere:
 		a := getArg(br.Arg, addrMap, nil)
-		br.Table = model.Table{Schema: a.schema(), Name: a.table()}
+		br.Table = a.getTable()
 		br.TableId = a.getTableId()
 		br.Attribute.Name = a.getAttributeName()
 		br.Attribute.Table = a.table()
@@ -684,21 +1172,94 @@ func helperWhere(builder *builder, addrMap map[uintptr]field, br *model.Where) {
 	}
 }
 
-func helperFilter(builder *builder, addrMap map[uintptr]field, filter *model.Where) *model.Where {
+// appendBetween appends the two bounds of the between as where arguments
+func appendBetween(builder *builder, operation *model.Where) {
+	valueOf := reflect.ValueOf(operation.Value.GetValue())
+	builder.query.Arguments = append(builder.query.Arguments, valueOf.Index(0).Interface(), valueOf.Index(1).Interface())
+	builder.whereArguments += 2
+}
+
+// openBetween changes a between of a filter with one zero bound to a comparison with the other bound,
+// as on the other filters a zero value is not used, so the zero bound is a open end of the range.
+// The between with the two bounds zero is not used by the filter
+func openBetween(filter *model.Where) {
+	valueOf := reflect.ValueOf(filter.Value.GetValue())
+	from, to := valueOf.Index(0), valueOf.Index(1)
+	if from.IsZero() == to.IsZero() {
+		return
+	}
+
+	not := filter.Operator == enum.NotBetween
+	filter.Type = enum.OperationWhere
+	switch {
+	case to.IsZero() && not:
+		filter.Operator, filter.Value = enum.Less, operand.Value{Value: from.Interface()}
+	case to.IsZero():
+		filter.Operator, filter.Value = enum.GreaterEquals, operand.Value{Value: from.Interface()}
+	case not:
+		filter.Operator, filter.Value = enum.Greater, operand.Value{Value: to.Interface()}
+	default:
+		filter.Operator, filter.Value = enum.LessEquals, operand.Value{Value: to.Interface()}
+	}
+}
+
+// emulateILike changes a ilike to a like of the lowercase column and value if the driver does not support
+// [enum.ILikeFeature], the lower of the column is also set on FunctionType so the baseline drivers render it
+func emulateILike(operation *model.Where, db *DB) {
+	var operator enum.OperatorType
+	switch operation.Operator {
+	case enum.ILike:
+		operator = enum.Like
+	case enum.NotILike:
+		operator = enum.NotLike
+	default:
+		return
+	}
+	if supports(db.driver, enum.ILikeFeature) {
+		return
+	}
+
+	column := model.Attribute{Table: operation.Attribute.Table, Name: operation.Attribute.Name}
+	operation.Operator = operator
+	operation.Attribute.Function = &model.Function{Type: enum.LowerFunction, Arguments: []model.FunctionArgument{{Attribute: &column}}}
+	operation.Attribute.FunctionType = enum.LowerFunction
+	operation.Value = operand.Value{Value: strings.ToLower(operation.Value.GetValue().(string))}
+}
+
+// mergeSubquery sets the subquery of the operation and appends the subquery arguments
+// as where arguments, the subquery is numbered and correlated on build. The subqueries of in
+// are merged only for the drivers with enum.SubqueryArgumentsFeature, the others merge them
+func mergeSubquery(builder *builder, operation *model.Where, subquery model.Query) {
+	operation.QueryIn = &subquery
+	operation.MergedIn = true
+	builder.query.Arguments = append(builder.query.Arguments, subquery.Arguments...)
+	builder.whereArguments += len(subquery.Arguments)
+}
+
+func helperFilter(builder *builder, addrMap databases, filter *model.Where) *model.Where {
 	switch filter.Type {
-	case enum.OperationWhere, enum.OperationInWhere:
+	case enum.OperationWhere, enum.OperationInWhere, enum.OperationBetweenWhere:
+		if filter.Type == enum.OperationBetweenWhere {
+			openBetween(filter)
+		}
 		if !reflect.ValueOf(filter.Value.GetValue()).IsZero() {
 			a := getArg(filter.Arg, addrMap, filter)
-			filter.Table = model.Table{Schema: a.schema(), Name: a.table()}
+			filter.Table = a.getTable()
 			filter.TableId = a.getTableId()
 			filter.Attribute.Name = a.getAttributeName()
 			filter.Attribute.Table = a.table()
+			emulateILike(filter, a.getDb())
+			appendAttribute(builder, filter.Attribute)
 
 			if filter.Type == enum.OperationWhere {
 				builder.query.Arguments = append(builder.query.Arguments, filter.Value.GetValue())
 				builder.whereArguments++
 			}
 
+			if filter.Type == enum.OperationBetweenWhere {
+				appendBetween(builder, filter)
+			}
+
 			if filter.Type == enum.OperationInWhere {
 				valueOf := reflect.ValueOf(filter.Value.GetValue())
 				switch valueOf.Kind() {
@@ -717,11 +1278,19 @@ func helperFilter(builder *builder, addrMap map[uintptr]field, filter *model.Whe
 				default:
 					if modelQuery, ok := valueOf.Interface().(model.Query); ok {
 						filter.QueryIn = &modelQuery
+						if supports(a.getDb().driver, enum.SubqueryArgumentsFeature) {
+							mergeSubquery(builder, filter, modelQuery)
+						}
 					}
 				}
 			}
 			return filter
 		}
+	case enum.OperationExistsWhere:
+		if modelQuery, ok := filter.Value.GetValue().(model.Query); ok {
+			mergeSubquery(builder, filter, modelQuery)
+			return filter
+		}
 	case enum.OperationAttributeWhere:
 		panic("goe: invalid filter call. try using the field operation on where.")
 	case enum.LogicalWhere:
@@ -742,25 +1311,47 @@ func helperFilter(builder *builder, addrMap map[uintptr]field, filter *model.Whe
 	return nil
 }
 
-func getArgsSelect(args ...any) argsSelect {
-	addrMap := addrMap.mapField
+func getArgsSelect(addrMap databases, args ...any) argsSelect {
 	fields := make([]fieldSelect, 0, len(args))
+	var nested []nestedTable
 
 	for _, arg := range args {
 		fieldOf := reflect.ValueOf(arg)
-		f := addrMap[uintptr(fieldOf.UnsafePointer())]
+		if fieldOf.Kind() == reflect.Pointer && addrMap.isTable(fieldOf.Type().Elem()) {
+			ts, tableFields := newTableScan(addrMap, fieldOf)
+			if len(tableFields) != 0 {
+				nested = append(nested, nestedTable{column: len(fields), tableScan: ts})
+				for _, f := range tableFields {
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+		f := addrMap.field(uintptr(fieldOf.UnsafePointer()))
 		if f != nil {
 			fields = append(fields, f)
 			continue
 		}
+		if w, ok := fieldOf.Interface().(model.Windower); ok {
+			fields = append(fields, createWindow(addrMap, w))
+			continue
+		}
+		if fn, ok := fieldOf.Interface().(model.FunctionType); ok {
+			fields = append(fields, createFunction(addrMap, fn))
+			continue
+		}
+		if e, ok := fieldOf.Interface().(model.Expressioner); ok {
+			fields = append(fields, createExpression(addrMap, e))
+			continue
+		}
+		if c, ok := fieldOf.Interface().(model.Caser); ok {
+			fields = append(fields, createCase(addrMap, c))
+			continue
+		}
 		if a, ok := fieldOf.Interface().(model.Attributer); ok {
-			f = addrMap[uintptr(reflect.ValueOf(a.GetField()).UnsafePointer())]
+			f = addrMap.field(uintptr(reflect.ValueOf(a.GetField()).UnsafePointer()))
 			if f != nil {
-				if a.Attribute(model.Body{}).AggregateType != 0 {
-					fields = append(fields, createAggregate(f, fieldOf.Elem().Interface()))
-					continue
-				}
-				fields = append(fields, createFunction(f, fieldOf.Elem().Interface()))
+				fields = append(fields, createAggregate(f, fieldOf.Elem().Interface()))
 			}
 		}
 	}
@@ -768,21 +1359,22 @@ func getArgsSelect(args ...any) argsSelect {
 	if len(fields) == 0 {
 		panic("goe: invalid argument. try sending a pointer to a database mapped argument")
 	}
+	setFunctionTables(fields)
 
-	return argsSelect{fields: fields, tableArgs: args}
+	return argsSelect{fields: fields, tableArgs: args, nested: nested}
 }
 
-func getArgsList(args ...any) argsSelect {
-	addrMap := addrMap.mapField
+func getArgsList(addrMap databases, args ...any) argsSelect {
 	fields := make([]fieldSelect, 0, len(args))
 	tableArgs := make([]any, 0, len(args))
 
 	for _, arg := range args {
 		structOf := reflect.ValueOf(arg).Elem()
-		var fieldOf reflect.Value
-		for i := 0; i < structOf.NumField(); i++ {
-			fieldOf = structOf.Field(i)
-			if f := addrMap[uintptr(fieldOf.Addr().UnsafePointer())]; f != nil {
+		for field, fieldOf := range fieldsOf(structOf) {
+			if isIgnored(field) {
+				continue
+			}
+			if f := addrMap.field(uintptr(fieldOf.Addr().UnsafePointer())); f != nil {
 				fields = append(fields, f)
 				tableArgs = append(tableArgs, fieldOf.Addr().Interface())
 			}
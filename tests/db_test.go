@@ -6,11 +6,18 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-goe/goe"
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
+	"github.com/go-goe/goe/query/where"
+	"github.com/go-goe/goe/utils"
 	"github.com/go-goe/postgres"
 	"github.com/go-goe/sqlite"
 	"github.com/google/uuid"
@@ -20,6 +27,7 @@ import (
 type Animal struct {
 	Name        string `goe:"index"`
 	HabitatId   *uuid.UUID
+	Habitat     *Habitat
 	InfoId      *[]byte
 	Id          int
 	AnimalFoods []AnimalFood
@@ -170,6 +178,41 @@ type Default struct {
 	Name string
 }
 
+type Legacy struct {
+	Code   int    `goe:"pk;column:lgc_code"`
+	Cached string `goe:"-"`
+	Name   string `goe:"column:lgc_name"`
+}
+
+type BaseModel struct {
+	Id        int
+	CreatedAt time.Time
+}
+
+type Post struct {
+	BaseModel
+	Title string
+}
+
+type Ticket struct {
+	Id      int
+	OwnerId int `goe:"fk:Person.Id"`
+	Title   string
+}
+
+type Order struct {
+	Code     string `goe:"pk"`
+	TenantId string `goe:"pk"`
+	Customer string
+}
+
+type OrderLine struct {
+	Id        int
+	OrderCode string `goe:"fk:Order.Code"`
+	TenantId  string `goe:"fk:Order.TenantId"`
+	Product   string
+}
+
 type Database struct {
 	Animal     *Animal
 	AnimalFood *AnimalFood
@@ -186,10 +229,23 @@ type Database struct {
 	Select         *Select
 	Page           *Page
 	Default        *Default
+	Legacy         *Legacy `goe:"table:legacy_table"`
+	Post           *Post
+	Ticket         *Ticket
 	*DropSchema
 	*goe.DB
 }
 
+// CompositeDatabase has the tables with composite foreign keys, opened apart
+// because the drivers without support fail to open them
+type CompositeDatabase struct {
+	Order     *Order
+	OrderLine *OrderLine
+	*goe.DB
+}
+
+const postgresDsn = "user=postgres password=postgres host=localhost port=5432 database=postgres"
+
 var db *Database
 
 var mapDriver = map[string]func() (*Database, error){
@@ -212,7 +268,7 @@ func Setup() (*Database, error) {
 
 func SetupPostgres() (*Database, error) {
 	var err error
-	db, err := goe.Open[Database](postgres.Open("user=postgres password=postgres host=localhost port=5432 database=postgres", postgres.NewConfig(postgres.Config{
+	db, err := goe.Open[Database](postgres.Open(postgresDsn, postgres.NewConfig(postgres.Config{
 		//Logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
 	})))
 	if err != nil {
@@ -246,6 +302,40 @@ func SetupSqlite() (*Database, error) {
 	return db, nil
 }
 
+// SetupComposite opens the CompositeDatabase on the GOE_DRIVER, skips the test if the driver does not support composite foreign keys
+func SetupComposite(t *testing.T) *CompositeDatabase {
+	var driver model.Driver
+	switch os.Getenv("GOE_DRIVER") {
+	case "PostgreSQL":
+		driver = postgres.Open(postgresDsn, postgres.NewConfig(postgres.Config{}))
+	case "SQLite":
+		driver = sqlite.Open(filepath.Join(os.TempDir(), "goe_composite.db"), sqlite.NewConfig(sqlite.Config{}))
+	}
+	db, err := goe.Open[CompositeDatabase](driver)
+	if errors.Is(err, goe.ErrUnsupported) {
+		t.Skipf("Skipping composite foreign keys: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Expected a connection, got error %v", err)
+	}
+	t.Cleanup(func() { goe.Close(db) })
+
+	err = goe.Migrate(db).AutoMigrate()
+	if err != nil {
+		t.Fatalf("Expected migrate, got error %v", err)
+	}
+	return db
+}
+
+// skipUnsupported skips the test if the driver does not support any of the features
+func skipUnsupported(t *testing.T, features ...enum.Feature) {
+	for _, feature := range features {
+		if !db.Supports(feature) {
+			t.Skipf("Skipping, the %v driver does not support the feature %v", db.Name(), feature)
+		}
+	}
+}
+
 func TestConnection(t *testing.T) {
 	_, err := Setup()
 	if err != nil {
@@ -299,13 +389,242 @@ func TestRace(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			raceDb, _ := mapDriver[os.Getenv("GOE_DRIVER")]()
-			goe.Close(raceDb)
+			raceDb, err := mapDriver[os.Getenv("GOE_DRIVER")]()
+			if err != nil {
+				t.Errorf("Expected a connection, got error %v", err)
+				return
+			}
+			defer goe.Close(raceDb)
+			_, err = goe.List(raceDb.Animal).Where(where.Equals(&raceDb.Animal.Id, 0)).AsSlice()
+			if err != nil {
+				t.Errorf("Expected list animals, got error %v", err)
+			}
 		}()
 	}
 	wg.Wait()
 }
 
+type InvalidNoPk struct {
+	Name string
+}
+
+type InvalidOrphan struct {
+	Id int
+}
+
+type InvalidTable struct {
+	Id      int
+	Meta    map[string]string
+	Tags    []string
+	Orphans []InvalidOrphan
+	OwnerId int    `goe:"fk:Missing.Id"`
+	Code    string `goe:"unique;index"`
+}
+
+type InvalidEmbedded struct {
+	*InvalidOrphan
+	Name string
+}
+
+type InvalidParent struct {
+	Id       int
+	Children []InvalidChild
+}
+
+type InvalidChild struct {
+	Id         int
+	OwnerId    int `goe:"fk:InvalidParent.Id"`
+	AssigneeId int `goe:"fk:InvalidParent.Id"`
+}
+
+type InvalidDatabase struct {
+	InvalidNoPk     *InvalidNoPk
+	InvalidTable    *InvalidTable
+	InvalidParent   *InvalidParent
+	InvalidChild    *InvalidChild
+	InvalidEmbedded *InvalidEmbedded
+	*goe.DB
+}
+
+func TestOpenDiagnostics(t *testing.T) {
+	_, err := goe.Open[InvalidDatabase](sqlite.Open(filepath.Join(os.TempDir(), "goe_invalid.db"), sqlite.NewConfig(sqlite.Config{})))
+	if err == nil {
+		t.Fatal("Expected a error, got nil")
+	}
+
+	for _, problem := range []string{
+		`struct "InvalidNoPk" don't have a primary key`,
+		`struct "InvalidTable" field "Meta" have a unsupported kind "map"`,
+		`struct "InvalidTable" field "OwnerId" have a invalid foreign key "Missing.Id"`,
+		`struct "InvalidTable" field "Code" have conflicting index definitions`,
+		`struct "InvalidParent" field "Children" have a ambiguous relation, matches ["InvalidChild.OwnerId" "InvalidChild.AssigneeId"]`,
+		`struct "InvalidEmbedded" field "InvalidOrphan" is a embedded pointer`,
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected problem %v, got %v", problem, err)
+		}
+	}
+
+	// the slices that are not a relation are skipped as before
+	for _, field := range []string{"Tags", "Orphans"} {
+		if strings.Contains(err.Error(), strconv.Quote(field)) {
+			t.Errorf("Expected %v skipped, got %v", field, err)
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	db, err := Setup()
+	if err != nil {
+		t.Fatalf("Expected a connection, got error %v", err)
+	}
+
+	metadata := db.Metadata()
+	legacy, ok := metadata.Table("Legacy")
+	if !ok {
+		t.Fatal("Expected table Legacy")
+	}
+	if legacy.Name != "legacy_table" {
+		t.Errorf("Expected legacy_table, got %v", legacy.Name)
+	}
+	code, ok := legacy.Column("Code")
+	if !ok || code.Name != "lgc_code" || !code.PrimaryKey {
+		t.Errorf("Expected primary key lgc_code, got %v", code)
+	}
+	if code.GoType != reflect.TypeFor[int]() {
+		t.Errorf("Expected Go type int, got %v", code.GoType)
+	}
+	if _, ok := legacy.Column("Cached"); ok {
+		t.Error("Expected ignored field Cached without column")
+	}
+}
+
+func TestCompositeMetadata(t *testing.T) {
+	db := SetupComposite(t)
+
+	orderLine, ok := db.Metadata().Table("OrderLine")
+	if !ok {
+		t.Fatal("Expected table OrderLine")
+	}
+	if len(orderLine.Relations) != 1 {
+		t.Fatalf("Expected 1 relation, got %v", len(orderLine.Relations))
+	}
+	relation := orderLine.Relations[0]
+	if relation.Type != enum.ManyToOneRelation || relation.TargetTable != "orders" || len(relation.Columns) != 2 || len(relation.TargetColumns) != 2 {
+		t.Errorf("Expected composite relation to orders, got %v", relation)
+	}
+}
+
+type prefixNaming struct{}
+
+func (prefixNaming) TableName(name string) string  { return "tb_" + strings.ToLower(name) }
+func (prefixNaming) ColumnName(name string) string { return "cl_" + strings.ToLower(name) }
+func (prefixNaming) IndexName(table, field string) string {
+	return "ix_" + table + "_" + strings.ToLower(field)
+}
+func (prefixNaming) ForeignKeyName(table, column string) string {
+	return utils.DefaultNamingStrategy{}.ForeignKeyName(table, column)
+}
+
+// foreignKeyNaming names only the foreign keys, the drivers without enum.ForeignKeyNameFeature can't use it
+type foreignKeyNaming struct {
+	utils.DefaultNamingStrategy
+}
+
+func (foreignKeyNaming) ForeignKeyName(table, column string) string {
+	return "fk_" + table + "_" + column
+}
+
+type Writer struct {
+	Id   int
+	Name string `goe:"index"`
+}
+
+type Book struct {
+	Id       int
+	Title    string
+	Isbn     string `goe:"column:book_isbn"`
+	WriterId int
+}
+
+type NamingDatabase struct {
+	Writer *Writer
+	Book   *Book `goe:"table:library_books"`
+	*goe.DB
+}
+
+func TestNamingStrategy(t *testing.T) {
+	db, err := goe.Open[NamingDatabase](sqlite.Open(filepath.Join(os.TempDir(), "goe_naming.db"), sqlite.NewConfig(sqlite.Config{
+		DatabaseConfig: model.DatabaseConfig{NamingStrategy: prefixNaming{}},
+	})))
+	if err != nil {
+		t.Fatalf("Expected open, got error %v", err)
+	}
+	defer goe.Close(db)
+
+	metadata := db.Metadata()
+	writer, ok := metadata.Table("Writer")
+	if !ok || writer.Name != "tb_writer" {
+		t.Fatalf("Expected table tb_writer, got %v", writer.Name)
+	}
+	if name, ok := writer.Column("Name"); !ok || name.Name != "cl_name" {
+		t.Errorf("Expected column cl_name, got %v", name.Name)
+	}
+	if len(writer.Indexes) != 1 || writer.Indexes[0].Name != "ix_tb_writer_name" {
+		t.Errorf("Expected index ix_tb_writer_name, got %v", writer.Indexes)
+	}
+
+	book, ok := metadata.Table("Book")
+	if !ok || book.Name != "library_books" {
+		t.Fatalf("Expected table library_books, got %v", book.Name)
+	}
+	if isbn, ok := book.Column("Isbn"); !ok || isbn.Name != "book_isbn" {
+		t.Errorf("Expected column book_isbn, got %v", isbn.Name)
+	}
+	if len(book.Relations) != 1 || book.Relations[0].ForeignKeyName != "library_books_cl_writerid_fkey" || book.Relations[0].TargetTable != "tb_writer" {
+		t.Errorf("Expected foreign key library_books_cl_writerid_fkey to tb_writer, got %v", book.Relations)
+	}
+
+	err = goe.Migrate(db).AutoMigrate()
+	if err != nil {
+		t.Fatalf("Expected migrate, got error %v", err)
+	}
+	err = goe.Migrate(db).OnTable("Book").RenameColumn("Isbn", "Code")
+	if err != nil {
+		t.Fatalf("Expected rename column book_isbn, got error %v", err)
+	}
+	err = goe.Migrate(db).OnTable("Book").DropColumn("Code")
+	if err != nil {
+		t.Fatalf("Expected drop column cl_code, got error %v", err)
+	}
+	err = goe.Migrate(db).OnTable("Book").DropTable()
+	if err != nil {
+		t.Fatalf("Expected drop table library_books, got error %v", err)
+	}
+	err = goe.Migrate(db).OnTable("Writer").DropTable()
+	if err != nil {
+		t.Fatalf("Expected drop table tb_writer, got error %v", err)
+	}
+}
+
+func TestForeignKeyNaming(t *testing.T) {
+	db, err := goe.Open[NamingDatabase](sqlite.Open(filepath.Join(os.TempDir(), "goe_foreign_key_naming.db"), sqlite.NewConfig(sqlite.Config{
+		DatabaseConfig: model.DatabaseConfig{NamingStrategy: foreignKeyNaming{}},
+	})))
+	if errors.Is(err, goe.ErrUnsupported) {
+		t.Skipf("Skipping foreign key names: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Expected open, got error %v", err)
+	}
+	defer goe.Close(db)
+
+	book, _ := db.Metadata().Table("Book")
+	if len(book.Relations) != 1 || book.Relations[0].ForeignKeyName != "fk_library_books_writer_id" {
+		t.Errorf("Expected foreign key fk_library_books_writer_id, got %v", book.Relations)
+	}
+}
+
 func TestMigrate(t *testing.T) {
 	db, err := Setup()
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/go-goe/goe"
+	"github.com/go-goe/goe/query/where"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
@@ -390,8 +391,160 @@ func TestInsert(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Insert_Table_Column_Tag",
+			testCase: func(t *testing.T) {
+				err = goe.Delete(db.Legacy).All()
+				if err != nil {
+					t.Fatalf("Expected a delete, got error: %v", err)
+				}
+				l := Legacy{Name: "Legacy"}
+				err = goe.Insert(db.Legacy).One(&l)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				ls, err := goe.Find(db.Legacy).ByValue(Legacy{Code: l.Code})
+				if err != nil {
+					t.Fatalf("Expected a find, got error: %v", err)
+				}
+
+				if ls.Name != l.Name {
+					t.Errorf("Expected %v, got %v", l.Name, ls.Name)
+				}
+			},
+		},
+		{
+			desc: "Insert_Ignored_Field",
+			testCase: func(t *testing.T) {
+				l := Legacy{Name: "Ignored", Cached: "Cached"}
+				err = goe.Insert(db.Legacy).One(&l)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				err = goe.Save(db.Legacy).One(Legacy{Code: l.Code, Cached: "Cached Save"})
+				if err != nil {
+					t.Fatalf("Expected a save, got error: %v", err)
+				}
+
+				ls, err := goe.List(db.Legacy).Where(where.Equals(&db.Legacy.Code, l.Code)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected a list, got error: %v", err)
+				}
+
+				if len(ls) != 1 {
+					t.Fatalf("Expected 1, got %v", len(ls))
+				}
+
+				if ls[0].Name != l.Name || ls[0].Cached != "" {
+					t.Errorf("Expected %v with empty cached, got %v and %v", l.Name, ls[0].Name, ls[0].Cached)
+				}
+			},
+		},
+		{
+			desc: "Insert_Embedded_Struct",
+			testCase: func(t *testing.T) {
+				p := Post{BaseModel: BaseModel{CreatedAt: time.Now().Truncate(time.Second)}, Title: "Post"}
+				err = goe.Insert(db.Post).One(&p)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				if p.Id == 0 {
+					t.Fatalf("Expected a returning id, got %v", p.Id)
+				}
+
+				err = goe.Save(db.Post).One(Post{BaseModel: BaseModel{Id: p.Id}, Title: "Post Save"})
+				if err != nil {
+					t.Fatalf("Expected a save, got error: %v", err)
+				}
+
+				ps, err := goe.Find(db.Post).ByValue(Post{BaseModel: BaseModel{Id: p.Id}})
+				if err != nil {
+					t.Fatalf("Expected a find, got error: %v", err)
+				}
+
+				if ps.Title != "Post Save" || !ps.CreatedAt.Equal(p.CreatedAt) {
+					t.Errorf("Expected %v created at %v, got %v created at %v", "Post Save", p.CreatedAt, ps.Title, ps.CreatedAt)
+				}
+			},
+		},
+		{
+			desc: "Insert_Foreign_Key_Tag",
+			testCase: func(t *testing.T) {
+				p := Person{Name: "Owner"}
+				err = goe.Insert(db.Person).One(&p)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				ticket := Ticket{OwnerId: p.Id, Title: "Ticket"}
+				err = goe.Insert(db.Ticket).One(&ticket)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				result, err := goe.Select[struct {
+					Owner string
+					Title string
+				}](&db.Person.Name, &db.Ticket.Title).
+					Join(&db.Person.Id, &db.Ticket.OwnerId).
+					Where(where.Equals(&db.Ticket.Id, ticket.Id)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected a select, got error: %v", err)
+				}
+
+				if len(result) != 1 || result[0].Owner != p.Name {
+					t.Errorf("Expected %v, got %v", p.Name, result)
+				}
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, tC.testCase)
 	}
 }
+
+func TestInsertCompositeForeignKey(t *testing.T) {
+	db := SetupComposite(t)
+
+	code := uuid.NewString()
+	orders := []Order{
+		{Code: code, TenantId: "a", Customer: "Customer A"},
+		{Code: code, TenantId: "b", Customer: "Customer B"},
+	}
+	err := goe.Insert(db.Order).All(orders)
+	if err != nil {
+		t.Fatalf("Expected a insert, got error: %v", err)
+	}
+
+	lines := []OrderLine{
+		{OrderCode: code, TenantId: "a", Product: "Product A"},
+		{OrderCode: code, TenantId: "b", Product: "Product B"},
+	}
+	err = goe.Insert(db.OrderLine).All(lines)
+	if err != nil {
+		t.Fatalf("Expected a insert, got error: %v", err)
+	}
+
+	// the join by the code is completed with the tenant by the composite foreign key
+	result, err := goe.Select[struct {
+		Customer string
+		Product  string
+	}](&db.Order.Customer, &db.OrderLine.Product).
+		Join(&db.Order.Code, &db.OrderLine.OrderCode).
+		Where(where.Equals(&db.Order.Code, code)).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected a select, got error: %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 lines, got %v", len(result))
+	}
+	for _, r := range result {
+		if r.Customer[len(r.Customer)-1] != r.Product[len(r.Product)-1] {
+			t.Errorf("Expected line joined by code and tenant, got %v", r)
+		}
+	}
+}
@@ -10,9 +10,13 @@ import (
 	"time"
 
 	"github.com/go-goe/goe"
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/model"
 	"github.com/go-goe/goe/query/aggregate"
+	"github.com/go-goe/goe/query/expr"
 	"github.com/go-goe/goe/query/function"
 	"github.com/go-goe/goe/query/where"
+	"github.com/go-goe/goe/query/window"
 	"github.com/google/uuid"
 )
 
@@ -355,6 +359,147 @@ func TestSelect(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Select_Nested_Functions",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.FunctionFeature)
+				for row, err := range goe.Select[struct {
+					Name    string
+					Lower   string
+					Length  int64
+					Initial string
+					Label   string
+				}](&db.Animal.Name,
+					function.ToLower(function.Trim(&db.Animal.Name)),
+					function.Length(&db.Animal.Name),
+					function.ToUpper(function.Substring(&db.Animal.Name, 1, 1)),
+					function.Concat(&db.Animal.Name, "-", function.Cast[string](&db.Animal.Id, "varchar(20)"))).Rows() {
+					if err != nil {
+						t.Fatalf("Expected select, got error: %v", err)
+					}
+					if strings.ToLower(strings.TrimSpace(row.Name)) != row.Lower {
+						t.Errorf("Expected %v, got: %v", strings.ToLower(strings.TrimSpace(row.Name)), row.Lower)
+					}
+					if int64(len([]rune(row.Name))) != row.Length {
+						t.Errorf("Expected %v, got: %v", len([]rune(row.Name)), row.Length)
+					}
+					if strings.ToUpper(row.Name[:1]) != row.Initial {
+						t.Errorf("Expected %v, got: %v", strings.ToUpper(row.Name[:1]), row.Initial)
+					}
+					if !strings.HasPrefix(row.Label, row.Name+"-") {
+						t.Errorf("Expected %v-id, got: %v", row.Name, row.Label)
+					}
+				}
+			},
+		},
+		{
+			desc: "Select_Coalesce_GroupBy",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.FunctionFeature, enum.PositionFeature)
+				habitat := function.Coalesce(function.Cast[string](&db.Animal.HabitatId, "varchar(36)"), "none")
+				result, err := goe.Select[struct {
+					Habitat string
+					Count   int64
+				}](habitat, aggregate.Count(&db.Animal.Id)).
+					GroupBy(habitat).
+					Where(where.Greater(function.Length(function.Trim(&db.Animal.Name)), function.Argument[int64](0))).
+					OrderByDesc(aggregate.Count(&db.Animal.Id)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got error: %v", err)
+				}
+				if len(result) != 4 {
+					t.Fatalf("Expected 4, got %v", len(result))
+				}
+				if result[0].Habitat != "none" || result[0].Count != int64(len(animals)-8) {
+					t.Errorf("Expected none with %v, got %v", len(animals)-8, result[0])
+				}
+			},
+		},
+		{
+			desc: "Select_Function_Arguments",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.FunctionFeature)
+				suffix := "' || 'x"
+				result, err := goe.Select[struct {
+					Label   string
+					Initial string
+				}](function.Concat(&db.Animal.Name, suffix), function.Substring(&db.Animal.Name, 1, 2)).
+					Where(where.Equals(function.Substring(&db.Animal.Name, 1, 3), function.Argument("Cat"))).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got error: %v", err)
+				}
+				if len(result) == 0 {
+					t.Fatal("Expected animals starting with Cat, got 0")
+				}
+				for _, r := range result {
+					if !strings.HasSuffix(r.Label, suffix) || r.Initial != "Ca" {
+						t.Errorf("Expected label ending with %q and Ca, got %q and %q", suffix, r.Label, r.Initial)
+					}
+				}
+			},
+		},
+		{
+			desc: "Select_Cast_Invalid_Panic",
+			testCase: func(t *testing.T) {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Error("Expected a panic on a invalid cast type")
+					}
+				}()
+				goe.Select[struct{ Code string }](function.Cast[string](&db.Animal.Id, "text); drop table animals; --")).AsSlice()
+			},
+		},
+		{
+			desc: "Select_Expression",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.ExpressionFeature)
+				result, err := goe.Select[struct {
+					Id     int
+					Double int
+					Next   int
+				}](&db.Animal.Id, expr.Add(&db.Animal.Id, &db.Animal.Id), expr.Add(&db.Animal.Id, expr.Argument(1))).
+					Where(where.Equals(expr.Mod(&db.Animal.Id, expr.Argument(2)), expr.Argument(0))).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got error: %v", err)
+				}
+				if len(result) == 0 {
+					t.Fatal("Expected animals with even id, got 0")
+				}
+				for _, r := range result {
+					if r.Id%2 != 0 {
+						t.Errorf("Expected a even id, got %v", r.Id)
+					}
+					if r.Double != r.Id*2 || r.Next != r.Id+1 {
+						t.Errorf("Expected %v and %v, got %v and %v", r.Id*2, r.Id+1, r.Double, r.Next)
+					}
+				}
+			},
+		},
+		{
+			desc: "Select_Case_GroupBy",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.CaseFeature, enum.PositionFeature)
+				habitat := expr.Case().When(where.IsNull(&db.Animal.HabitatId), "none").Else("habitat")
+				result, err := goe.Select[struct {
+					Habitat string
+					Count   int64
+				}](habitat, aggregate.Count(&db.Animal.Id)).
+					GroupBy(habitat).
+					OrderByAsc(habitat).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got error: %v", err)
+				}
+				if len(result) != 2 {
+					t.Fatalf("Expected 2, got %v", len(result))
+				}
+				if result[0].Habitat != "habitat" || result[0].Count != 8 {
+					t.Errorf("Expected habitat with 8, got %v", result[0])
+				}
+				if result[1].Habitat != "none" || result[1].Count != int64(len(animals)-8) {
+					t.Errorf("Expected none with %v, got %v", len(animals)-8, result[1])
+				}
+			},
+		},
 		{
 			desc: "List_Filter_Order",
 			testCase: func(t *testing.T) {
@@ -722,6 +867,64 @@ func TestSelect(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Select_Where_Exists",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.ExistsFeature)
+				querySelect := goe.Select[any](&db.AnimalFood.AnimalId).
+					Where(where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id)).AsQuery()
+
+				a, err := goe.List(db.Animal).Where(where.Exists(querySelect)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected a select where exists, got error: %v", err)
+				}
+				if len(a) != len(animalFoods) {
+					t.Errorf("Expected %v, got %v", len(animalFoods), len(a))
+				}
+
+				//exists with arguments on the subquery and on the outer query
+				querySelect = goe.Select[any](&db.AnimalFood.AnimalId).
+					Join(&db.AnimalFood.FoodId, &db.Food.Id).
+					Where(
+						where.And(
+							where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id),
+							where.Equals(&db.Food.Name, foods[0].Name),
+						)).AsQuery()
+
+				a, err = goe.List(db.Animal).Where(
+					where.And(
+						where.And(
+							where.NotEquals(&db.Animal.Id, animals[1].Id),
+							where.Exists(querySelect)),
+						where.LessEquals(&db.Animal.Id, animals[2].Id),
+					)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected a select where exists, got error: %v", err)
+				}
+				if len(a) != 1 {
+					t.Fatalf("Expected 1, got %v", len(a))
+				}
+				if a[0].Id != animals[0].Id {
+					t.Errorf("Expected %v, got %v", animals[0].Id, a[0].Id)
+				}
+			},
+		},
+		{
+			desc: "Select_Where_NotExists",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.ExistsFeature)
+				querySelect := goe.Select[any](&db.AnimalFood.AnimalId).
+					Where(where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id)).AsQuery()
+
+				a, err := goe.List(db.Animal).Where(where.NotExists(querySelect)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected a select where not exists, got error: %v", err)
+				}
+				if len(a) != len(animals)-len(animalFoods) {
+					t.Errorf("Expected %v, got %v", len(animals)-len(animalFoods), len(a))
+				}
+			},
+		},
 		{
 			desc: "List_As_Pagination",
 			testCase: func(t *testing.T) {
@@ -1039,6 +1242,109 @@ func TestSelect(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Select_Where_ILike",
+			testCase: func(t *testing.T) {
+				a := runSelect(t, goe.List(db.Animal).
+					Where(where.ILike(&db.Animal.Name, "%CAT%")).Rows())
+				if len(a) != 3 {
+					t.Errorf("Expected %v animals, got %v", 3, len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Where(where.NotILike(&db.Animal.Name, "%CAT%")).Rows())
+				if len(a) != len(animals)-3 {
+					t.Errorf("Expected %v animals, got %v", len(animals)-3, len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Where_Regex",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.RegexFeature)
+				a := runSelect(t, goe.List(db.Animal).
+					Where(where.Regex(&db.Animal.Name, "^(Cat|Dog)$")).Rows())
+				if len(a) != 2 {
+					t.Errorf("Expected %v animals, got %v", 2, len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Where(where.NotRegex(&db.Animal.Name, "^(Cat|Dog)$")).Rows())
+				if len(a) != len(animals)-2 {
+					t.Errorf("Expected %v animals, got %v", len(animals)-2, len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Where_Between",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.BetweenFeature)
+				a := runSelect(t, goe.List(db.Animal).
+					Where(where.Between(&db.Animal.Id, animals[0].Id, animals[2].Id)).Rows())
+				if len(a) != 3 {
+					t.Errorf("Expected %v animals, got %v", 3, len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Where(
+						where.And(
+							where.NotBetween(&db.Animal.Id, animals[0].Id, animals[2].Id),
+							where.Between(&db.Animal.HabitatId, &habitats[1].Id, &habitats[1].Id),
+						)).Rows())
+				if len(a) != 4 {
+					t.Errorf("Expected %v animals, got %v", 4, len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Filter_Between_Zero",
+			testCase: func(t *testing.T) {
+				a := runSelect(t, goe.List(db.Animal).
+					Filter(where.Between(&db.Animal.Id, 0, animals[2].Id)).Rows())
+				b := runSelect(t, goe.List(db.Animal).
+					Where(where.LessEquals(&db.Animal.Id, animals[2].Id)).Rows())
+				if len(a) != len(b) {
+					t.Errorf("Expected %v animals, got %v", len(b), len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Filter(where.NotBetween(&db.Animal.Id, animals[2].Id, 0)).Rows())
+				b = runSelect(t, goe.List(db.Animal).
+					Where(where.Less(&db.Animal.Id, animals[2].Id)).Rows())
+				if len(a) != len(b) {
+					t.Errorf("Expected %v animals, got %v", len(b), len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Filter(where.Between(&db.Animal.Id, 0, 0)).Rows())
+				if len(a) != len(animals) {
+					t.Errorf("Expected %v animals, got %v", len(animals), len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Where_Is_Null",
+			testCase: func(t *testing.T) {
+				a := runSelect(t, goe.List(db.Animal).
+					Where(where.IsNull(&db.Animal.HabitatId)).Rows())
+				if len(a) != len(animals)-8 {
+					t.Errorf("Expected %v animals, got %v", len(animals)-8, len(a))
+				}
+
+				a = runSelect(t, goe.List(db.Animal).
+					Where(where.IsNotNull(&db.Animal.HabitatId)).Rows())
+				if len(a) != 8 {
+					t.Errorf("Expected %v animals, got %v", 8, len(a))
+				}
+
+				//non-pointer field
+				a = runSelect(t, goe.List(db.Animal).
+					Where(where.IsNull(&db.Animal.Name)).Rows())
+				if len(a) != 0 {
+					t.Errorf("Expected %v animals, got %v", 0, len(a))
+				}
+			},
+		},
 		{
 			desc: "Select_Where_Equals_Nil",
 			testCase: func(t *testing.T) {
@@ -1125,6 +1431,138 @@ func TestSelect(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Select_Join_On",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.JoinOnFeature)
+				a := runSelect(t, goe.List(db.Animal).
+					JoinOn(db.AnimalFood, where.And(
+						where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id),
+						where.Equals(&db.AnimalFood.FoodId, foods[0].Id),
+					)).Rows())
+				if len(a) != len(animalFoods) {
+					t.Errorf("Expected %v animals, got %v", len(animalFoods), len(a))
+				}
+
+				//arguments on the join and on the where
+				a = runSelect(t, goe.List(db.Animal).
+					JoinOn(db.AnimalFood, where.And(
+						where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id),
+						where.Equals(&db.AnimalFood.FoodId, foods[0].Id),
+					)).
+					Where(where.Equals(&db.Animal.Name, animals[1].Name)).Rows())
+				if len(a) != 1 {
+					t.Fatalf("Expected 1 animal, got %v", len(a))
+				}
+				if a[0].Name != animals[1].Name {
+					t.Errorf("Expected %v, got %v", animals[1].Name, a[0].Name)
+				}
+
+				//the condition on the left join keeps the animals without match
+				f := runSelect(t, goe.Select[struct {
+					Name   string
+					FoodId *uuid.UUID
+				}](&db.Animal.Name, &db.AnimalFood.FoodId).
+					LeftJoinOn(db.AnimalFood, where.And(
+						where.EqualsArg[int](&db.AnimalFood.AnimalId, &db.Animal.Id),
+						where.NotEquals(&db.AnimalFood.FoodId, foods[0].Id),
+					)).Rows())
+				if len(f) != len(animals) {
+					t.Errorf("Expected %v animals, got %v", len(animals), len(f))
+				}
+				for _, r := range f {
+					if r.FoodId != nil {
+						t.Errorf("Expected nil food, got %v", *r.FoodId)
+					}
+				}
+			},
+		},
+		{
+			desc: "Select_Full_Join",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.FullJoinFeature)
+				a := runSelect(t, goe.Select[struct {
+					Name   *string
+					FoodId *uuid.UUID
+				}](&db.Animal.Name, &db.AnimalFood.FoodId).
+					FullJoin(&db.Animal.Id, &db.AnimalFood.AnimalId).Rows())
+				if len(a) != len(animals) {
+					t.Errorf("Expected %v rows, got %v", len(animals), len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Cross_Join",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.CrossJoinFeature)
+				a := runSelect(t, goe.Select[struct {
+					Animal string
+					Food   string
+				}](&db.Animal.Name, &db.Food.Name).
+					CrossJoin(db.Food).Rows())
+				if len(a) != len(animals)*len(foods) {
+					t.Errorf("Expected %v rows, got %v", len(animals)*len(foods), len(a))
+				}
+			},
+		},
+		{
+			desc: "Select_Union",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.CompoundFeature, enum.FromQueryFeature)
+				type name struct {
+					Name string
+				}
+				cat := goe.Select[name](&db.Animal.Name).Where(where.Equals(&db.Animal.Name, animals[0].Name))
+
+				a := runSelect(t, goe.Union(cat, cat).Rows())
+				if len(a) != 1 {
+					t.Errorf("Expected 1, got %v", len(a))
+				}
+
+				a = runSelect(t, goe.UnionAll(cat, cat).Rows())
+				if len(a) != 2 {
+					t.Errorf("Expected 2, got %v", len(a))
+				}
+
+				a = runSelect(t, goe.Union(goe.Select[name](&db.Animal.Name), goe.Select[name](&db.Food.Name)).
+					OrderByAsc(&db.Animal.Name).Take(5).Rows())
+				if len(a) != 5 {
+					t.Errorf("Expected 5, got %v", len(a))
+				}
+
+				p, err := goe.Union(goe.Select[name](&db.Animal.Name), goe.Select[name](&db.Food.Name)).AsPagination(1, 10)
+				if err != nil {
+					t.Fatalf("Expected pagination, got error: %v", err)
+				}
+				if p.TotalValues != int64(len(animals)+len(foods)) {
+					t.Errorf("Expected %v, got %v", len(animals)+len(foods), p.TotalValues)
+				}
+			},
+		},
+		{
+			desc: "Select_Intersect_Except",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.CompoundFeature, enum.FromQueryFeature)
+				type name struct {
+					Name string
+				}
+				first := goe.Select[name](&db.Animal.Name).Where(where.LessEquals(&db.Animal.Id, animals[2].Id))
+				second := goe.Select[name](&db.Animal.Name).Where(where.Equals(&db.Animal.Name, animals[1].Name))
+
+				a := runSelect(t, goe.Intersect(first, second).Rows())
+				if len(a) != 1 {
+					t.Fatalf("Expected 1, got %v", len(a))
+				}
+				if a[0].Name != animals[1].Name {
+					t.Errorf("Expected %v, got %v", animals[1].Name, a[0].Name)
+				}
+
+				a = runSelect(t, goe.Except(first, second).Rows())
+				if len(a) != 2 {
+					t.Errorf("Expected 2, got %v", len(a))
+				}
+			},
+		},
 		{
 			desc: "Select_Join_Where",
 			testCase: func(t *testing.T) {
@@ -1418,6 +1856,195 @@ func TestSelect(t *testing.T) {
 				wg.Wait()
 			},
 		},
+		{
+			desc: "Select_Join_Alias",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.AliasFeature)
+				err = goe.Delete(db.Page).All()
+				if err != nil {
+					t.Fatalf("Expected delete, got: %v", err)
+				}
+
+				pages := []Page{{Number: 1}, {Number: 2}, {Number: 3}}
+				for i := range pages {
+					if i > 0 {
+						pages[i].PageIDPrev = &pages[i-1].ID
+					}
+					err = goe.Insert(db.Page).One(&pages[i])
+					if err != nil {
+						t.Fatalf("Expected insert page, got: %v", err)
+					}
+				}
+
+				ctx, prev := goe.Alias(context.Background(), db.Page, "prev")
+				ctx, first := goe.Alias(ctx, db.Page, "first")
+				if _, same := goe.Alias(ctx, db.Page, "prev"); same != prev {
+					t.Error("Expected the same alias")
+				}
+
+				result, err := goe.SelectContext[struct {
+					Number   int
+					Previous int
+				}](ctx, &db.Page.Number, &prev.Number).
+					Join(&db.Page.PageIDPrev, &prev.ID).
+					OrderByAsc(&db.Page.Number).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got: %v", err)
+				}
+				if len(result) != 2 {
+					t.Fatalf("Expected 2, got %v", len(result))
+				}
+				if result[0].Number != 2 || result[0].Previous != 1 || result[1].Number != 3 || result[1].Previous != 2 {
+					t.Errorf("Expected pages 2 and 3 with the previous, got %v", result)
+				}
+
+				//the same table joined twice
+				chain, err := goe.SelectContext[struct {
+					Number   int
+					Previous int
+					First    *int
+				}](ctx, &db.Page.Number, &prev.Number, &first.Number).
+					Join(&db.Page.PageIDPrev, &prev.ID).
+					LeftJoin(&prev.PageIDPrev, &first.ID).
+					Where(where.Equals(&prev.Number, 2)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got: %v", err)
+				}
+				if len(chain) != 1 {
+					t.Fatalf("Expected 1, got %v", len(chain))
+				}
+				if chain[0].Number != 3 || chain[0].First == nil || *chain[0].First != 1 {
+					t.Errorf("Expected page 3 with first page 1, got %v", chain[0])
+				}
+			},
+		},
+		{
+			desc: "Select_With",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.WithFeature, enum.JoinOnFeature)
+				type habitatCount struct {
+					HabitatId uuid.UUID
+					Animals   int64
+				}
+				ctx, counts := goe.With(context.Background(), "habitat_counts", goe.Select[habitatCount](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+					Where(where.IsNotNull(&db.Animal.HabitatId)).
+					GroupBy(&db.Animal.HabitatId))
+
+				result, err := goe.SelectContext[struct {
+					Name    string
+					Animals int64
+				}](ctx, &db.Habitat.Name, &counts.Animals).
+					JoinOn(counts, where.EqualsArg[uuid.UUID](&db.Habitat.Id, &counts.HabitatId)).
+					Where(where.Greater(&counts.Animals, int64(1))).
+					OrderByDesc(&counts.Animals).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select with, got: %v", err)
+				}
+				if len(result) != 2 {
+					t.Fatalf("Expected 2, got %v", len(result))
+				}
+				if result[0].Name != habitats[1].Name || result[0].Animals != 5 {
+					t.Errorf("Expected %v with 5 animals, got %v", habitats[1].Name, result[0])
+				}
+			},
+		},
+		{
+			desc: "Select_With_Expression_Arguments",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.WithFeature, enum.JoinOnFeature, enum.ExpressionFeature)
+				type habitatCount struct {
+					HabitatId uuid.UUID
+					Animals   int64
+				}
+				// the arguments are sent on the order: with, select expression and where
+				ctx, counts := goe.With(context.Background(), "habitat_named_counts", goe.Select[habitatCount](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+					Where(where.And(where.IsNotNull(&db.Animal.HabitatId), where.NotEquals(&db.Animal.Name, "none"))).
+					GroupBy(&db.Animal.HabitatId))
+
+				result, err := goe.SelectContext[struct {
+					Name    string
+					Animals int64
+				}](ctx, &db.Habitat.Name, expr.Add(&counts.Animals, expr.Argument(int64(100)))).
+					JoinOn(counts, where.EqualsArg[uuid.UUID](&db.Habitat.Id, &counts.HabitatId)).
+					Where(where.Greater(&counts.Animals, int64(1))).
+					OrderByDesc(&counts.Animals).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select with expression, got: %v", err)
+				}
+				if len(result) != 2 {
+					t.Fatalf("Expected 2, got %v", len(result))
+				}
+				if result[0].Name != habitats[1].Name || result[0].Animals != 105 {
+					t.Errorf("Expected %v with 105, got %v", habitats[1].Name, result[0])
+				}
+			},
+		},
+		{
+			desc: "Select_With_Recursive",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.WithFeature, enum.CompoundFeature)
+				type page struct {
+					ID     int
+					Number int
+				}
+				ctx, tree := goe.WithRecursive(context.Background(), "tree",
+					goe.Select[page](&db.Page.ID, &db.Page.Number).Where(where.Equals(&db.Page.Number, 1)),
+					func(ctx context.Context, tree *page) model.Query {
+						return goe.SelectContext[page](ctx, &db.Page.ID, &db.Page.Number).
+							Join(&db.Page.PageIDPrev, &tree.ID).AsQuery()
+					})
+
+				result, err := goe.ListContext(ctx, tree).OrderByAsc(&tree.Number).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select with recursive, got: %v", err)
+				}
+				if len(result) != 3 {
+					t.Fatalf("Expected 3, got %v", len(result))
+				}
+				for i := range result {
+					if result[i].Number != i+1 {
+						t.Errorf("Expected page %v, got %v", i+1, result[i].Number)
+					}
+				}
+			},
+		},
+		{
+			desc: "Select_Window",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.WindowFeature)
+				result, err := goe.Select[struct {
+					Name     string
+					Number   int64
+					Animals  int64
+					Previous *string
+				}](&db.Animal.Name,
+					window.RowNumber().PartitionBy(&db.Animal.HabitatId).OrderByAsc(&db.Animal.Name, &db.Animal.Id),
+					window.Count(&db.Animal.Id).PartitionBy(&db.Animal.HabitatId),
+					window.Lag(&db.Animal.Name, 1).PartitionBy(&db.Animal.HabitatId).OrderByAsc(&db.Animal.Name, &db.Animal.Id)).
+					Where(where.Equals(&db.Animal.HabitatId, &habitats[1].Id)).
+					OrderByAsc(&db.Animal.Name, &db.Animal.Id).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select window, got: %v", err)
+				}
+				if len(result) != 5 {
+					t.Fatalf("Expected 5, got %v", len(result))
+				}
+				for i := range result {
+					if result[i].Number != int64(i+1) {
+						t.Errorf("Expected row number %v, got %v", i+1, result[i].Number)
+					}
+					if result[i].Animals != 5 {
+						t.Errorf("Expected 5 animals, got %v", result[i].Animals)
+					}
+					if i == 0 && result[i].Previous != nil {
+						t.Errorf("Expected nil, got %v", *result[i].Previous)
+					}
+					if i != 0 && (result[i].Previous == nil || *result[i].Previous != result[i-1].Name) {
+						t.Errorf("Expected %v, got %v", result[i-1].Name, result[i].Previous)
+					}
+				}
+			},
+		},
 		{
 			desc: "Select_As_Pagination_Total_Zero",
 			testCase: func(t *testing.T) {
@@ -1477,6 +2104,282 @@ func TestSelect(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "List_Include",
+			testCase: func(t *testing.T) {
+				result, err := goe.List(db.Habitat).
+					Include(&db.Habitat.Animals).
+					Where(where.Equals(&db.Habitat.Id, habitats[0].Id)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected list, got: %v", err)
+				}
+				if len(result) != 1 {
+					t.Fatalf("Expected 1 habitat, got %v", len(result))
+				}
+				if len(result[0].Animals) != 2 {
+					t.Errorf("Expected 2 animals, got %v", len(result[0].Animals))
+				}
+				for _, a := range result[0].Animals {
+					if *a.HabitatId != habitats[0].Id {
+						t.Errorf("Expected animal of habitat %v, got %v", habitats[0].Id, *a.HabitatId)
+					}
+				}
+			},
+		},
+		{
+			desc: "List_Include_Nested",
+			testCase: func(t *testing.T) {
+				result, err := goe.List(db.Weather).
+					Include(&db.Weather.Habitats, &db.Habitat.Animals).
+					Where(where.Equals(&db.Weather.Id, weathers[0].Id)).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected list, got: %v", err)
+				}
+				if len(result) != 1 || len(result[0].Habitats) != 2 {
+					t.Fatalf("Expected 1 weather with 2 habitats, got %v", result)
+				}
+
+				var count int
+				for _, h := range result[0].Habitats {
+					count += len(h.Animals)
+				}
+				if count != 3 {
+					t.Errorf("Expected 3 animals, got %v", count)
+				}
+			},
+		},
+		{
+			desc: "List_Include_Belongs_To",
+			testCase: func(t *testing.T) {
+				result, err := goe.List(db.Animal).
+					Include(&db.Animal.Habitat).
+					OrderByAsc(&db.Animal.Id).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected list, got: %v", err)
+				}
+				if len(result) != len(animals) {
+					t.Fatalf("Expected %v animals, got %v", len(animals), len(result))
+				}
+				for _, a := range result {
+					if a.HabitatId == nil {
+						if a.Habitat != nil {
+							t.Errorf("Expected nil habitat on %v, got %v", a.Name, a.Habitat)
+						}
+						continue
+					}
+					if a.Habitat == nil || a.Habitat.Id != *a.HabitatId {
+						t.Errorf("Expected habitat %v on %v, got %v", *a.HabitatId, a.Name, a.Habitat)
+					}
+				}
+			},
+		},
+		{
+			desc: "List_Include_Rows_Panic",
+			testCase: func(t *testing.T) {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Error("Expected a panic on Rows with Include")
+					}
+				}()
+				goe.List(db.Habitat).Include(&db.Habitat.Animals).Rows()
+			},
+		},
+		{
+			desc: "List_Include_Join",
+			testCase: func(t *testing.T) {
+				result, err := goe.List(db.Animal).
+					IncludeJoin(&db.Animal.Habitat).
+					Where(where.Equals(&db.Animal.Name, "Cat")).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected list, got: %v", err)
+				}
+				if len(result) != 1 {
+					t.Fatalf("Expected 1 animal, got %v", len(result))
+				}
+				if result[0].Habitat == nil || result[0].Habitat.Name != habitats[0].Name {
+					t.Errorf("Expected habitat %v, got %v", habitats[0].Name, result[0].Habitat)
+				}
+
+				result, err = goe.List(db.Animal).
+					IncludeJoin(&db.Animal.Habitat).
+					Where(where.Equals(&db.Animal.Name, "Whale")).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected list, got: %v", err)
+				}
+				if len(result) != 1 || result[0].Habitat != nil {
+					t.Errorf("Expected 1 animal without habitat, got %v", result)
+				}
+			},
+		},
+		{
+			desc: "Select_Having",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.HavingFeature)
+				result, err := goe.Select[struct {
+					Name  string
+					Count int64
+				}](&db.Habitat.Name, aggregate.Count(&db.Animal.Id)).
+					Join(&db.Animal.HabitatId, &db.Habitat.Id).
+					GroupBy(&db.Habitat.Name).
+					Having(where.Greater(aggregate.Count(&db.Animal.Id), aggregate.Argument(aggregate.Count(&db.Animal.Id), 1))).
+					OrderByAsc(&db.Habitat.Name).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected having, got: %v", err)
+				}
+				if len(result) != 2 {
+					t.Fatalf("Expected 2 habitats, got %v", len(result))
+				}
+				if result[0].Name != habitats[0].Name || result[0].Count != 2 {
+					t.Errorf("Expected %v with 2 animals, got %v with %v", habitats[0].Name, result[0].Name, result[0].Count)
+				}
+				if result[1].Name != habitats[1].Name || result[1].Count != 5 {
+					t.Errorf("Expected %v with 5 animals, got %v with %v", habitats[1].Name, result[1].Name, result[1].Count)
+				}
+			},
+		},
+		{
+			desc: "Select_Distinct",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.DistinctFeature)
+				result, err := goe.Select[struct{ HabitatId *uuid.UUID }](&db.Animal.HabitatId).Distinct().AsSlice()
+				if err != nil {
+					t.Fatalf("Expected distinct, got: %v", err)
+				}
+				// habitats 0, 1, 2 and animals without habitat
+				if len(result) != 4 {
+					t.Errorf("Expected 4 habitats, got %v", len(result))
+				}
+			},
+		},
+		{
+			desc: "Select_Distinct_Unsupported",
+			testCase: func(t *testing.T) {
+				if db.Supports(enum.DistinctOnFeature) {
+					t.Skip("Skipping, the driver supports DISTINCT ON")
+				}
+				_, err := goe.List(db.Animal).DistinctOn(&db.Animal.HabitatId).OrderByAsc(&db.Animal.HabitatId).AsSlice()
+				if !errors.Is(err, goe.ErrUnsupported) {
+					t.Errorf("Expected goe.ErrUnsupported, got: %v", err)
+				}
+			},
+		},
+		{
+			desc: "Select_Pagination_Distinct",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.DistinctFeature, enum.FromQueryFeature)
+				p, err := goe.Select[struct{ HabitatId *uuid.UUID }](&db.Animal.HabitatId).
+					Distinct().AsPagination(1, 3)
+				if err != nil {
+					t.Fatalf("Expected pagination, got: %v", err)
+				}
+				if p.TotalValues != 4 || p.TotalPages != 2 || len(p.Values) != 3 {
+					t.Errorf("Expected 4 values on 2 pages, got %v values on %v pages", p.TotalValues, p.TotalPages)
+				}
+			},
+		},
+		{
+			desc: "Select_Pagination_Group_By",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.FromQueryFeature)
+				p, err := goe.Select[struct {
+					HabitatId *uuid.UUID
+					Count     int64
+				}](&db.Animal.HabitatId, aggregate.Count(&db.Animal.Id)).
+					GroupBy(&db.Animal.HabitatId).AsPagination(1, 10)
+				if err != nil {
+					t.Fatalf("Expected pagination, got: %v", err)
+				}
+				if p.TotalValues != 4 {
+					t.Errorf("Expected 4 groups, got %v", p.TotalValues)
+				}
+			},
+		},
+		{
+			desc: "List_Cursor",
+			testCase: func(t *testing.T) {
+				query := goe.List(db.Animal).OrderByDesc(&db.Animal.Name)
+
+				var ids []int
+				seen := make(map[int]bool)
+				var last *goe.CursorPage[Animal]
+				p, err := query.AsCursor("", 5)
+				for err == nil {
+					for _, a := range p.Values {
+						if seen[a.Id] {
+							t.Errorf("Expected animal %v once, got repeated", a.Id)
+						}
+						seen[a.Id] = true
+						ids = append(ids, a.Id)
+					}
+					last = p
+					if !p.HasNextPage {
+						break
+					}
+					p, err = query.AsCursor(p.Next, 5)
+				}
+				if err != nil {
+					t.Fatalf("Expected cursor, got: %v", err)
+				}
+				if len(ids) != len(animals) {
+					t.Fatalf("Expected %v animals, got %v", len(animals), len(ids))
+				}
+
+				p, err = query.AsCursor(last.Previous, 5)
+				if err != nil {
+					t.Fatalf("Expected previous cursor, got: %v", err)
+				}
+				previous := ids[len(ids)-last.PageValues-5 : len(ids)-last.PageValues]
+				for i, a := range p.Values {
+					if a.Id != previous[i] {
+						t.Errorf("Expected animal %v on previous page, got %v", previous[i], a.Id)
+					}
+				}
+				if !p.HasNextPage {
+					t.Error("Expected next page on previous page, got false")
+				}
+
+				_, err = query.AsCursor(last.Previous+"x", 5)
+				if !errors.Is(err, goe.ErrInvalidCursor) {
+					t.Errorf("Expected goe.ErrInvalidCursor, got: %v", err)
+				}
+
+				_, err = goe.List(db.Animal).Where(where.NotEquals(&db.Animal.Name, "")).OrderByDesc(&db.Animal.Name).AsCursor(last.Previous, 5)
+				if !errors.Is(err, goe.ErrInvalidCursor) {
+					t.Errorf("Expected goe.ErrInvalidCursor for a cursor of other where, got: %v", err)
+				}
+			},
+		},
+		{
+			desc: "Select_Nested_Struct",
+			testCase: func(t *testing.T) {
+				result, err := goe.Select[struct {
+					Animal  Animal
+					Habitat *Habitat
+				}](db.Animal, db.Habitat).
+					LeftJoin(&db.Animal.HabitatId, &db.Habitat.Id).
+					OrderByAsc(&db.Animal.Id).AsSlice()
+				if err != nil {
+					t.Fatalf("Expected select, got: %v", err)
+				}
+				if len(result) != len(animals) {
+					t.Fatalf("Expected %v rows, got %v", len(animals), len(result))
+				}
+				for i, r := range result {
+					if r.Animal.Name != animals[i].Name {
+						t.Errorf("Expected animal %v, got %v", animals[i].Name, r.Animal.Name)
+					}
+					if r.Animal.HabitatId == nil {
+						if r.Habitat != nil {
+							t.Errorf("Expected nil habitat on %v, got %v", r.Animal.Name, r.Habitat)
+						}
+						continue
+					}
+					if r.Habitat == nil || r.Habitat.Id != *r.Animal.HabitatId {
+						t.Errorf("Expected habitat %v on %v, got %v", *r.Animal.HabitatId, r.Animal.Name, r.Habitat)
+					}
+				}
+			},
+		},
 	}
 	for _, tC := range testCases {
 		t.Run(tC.desc, tC.testCase)
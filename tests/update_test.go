@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/go-goe/goe"
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/expr"
 	"github.com/go-goe/goe/query/update"
 	"github.com/go-goe/goe/query/where"
 	"github.com/google/uuid"
@@ -116,6 +118,35 @@ func TestUpdate(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Save_Without_Sets",
+			testCase: func(t *testing.T) {
+				l := Legacy{Name: "Without Sets"}
+				err = goe.Insert(db.Legacy).One(&l)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				err = goe.Save(db.Legacy).One(Legacy{Code: l.Code})
+				if err != nil {
+					t.Fatalf("Expected a skipped save with only the primary key, got error: %v", err)
+				}
+
+				err = goe.Save(db.Legacy).One(Legacy{Code: l.Code, Cached: "Cached"})
+				if err != nil {
+					t.Fatalf("Expected a skipped save with only the primary key and a ignored field, got error: %v", err)
+				}
+
+				var ls *Legacy
+				ls, err = goe.Find(db.Legacy).ByValue(Legacy{Code: l.Code})
+				if err != nil {
+					t.Fatalf("Expected a find, got error: %v", err)
+				}
+				if ls.Name != l.Name {
+					t.Errorf("Expected %v, got %v", l.Name, ls.Name)
+				}
+			},
+		},
 		{
 			desc: "Save_Flag",
 			testCase: func(t *testing.T) {
@@ -676,6 +707,55 @@ func TestUpdate(t *testing.T) {
 				}
 			},
 		},
+		{
+			desc: "Update_Expression",
+			testCase: func(t *testing.T) {
+				skipUnsupported(t, enum.ExpressionFeature)
+				f := Flag{
+					Id:      uuid.New(),
+					Name:    "Flag_Expression",
+					Float64: 2.5,
+					Int:     1,
+					Price:   decimal.NewFromUint64(10),
+				}
+				err = goe.Insert(db.Flag).One(&f)
+				if err != nil {
+					t.Fatalf("Expected a insert, got error: %v", err)
+				}
+
+				err = goe.Update(db.Flag).
+					Sets(
+						update.Expression(&db.Flag.Int, expr.Add(&db.Flag.Int, expr.Argument(10))),
+						update.Expression(&db.Flag.Float64, expr.Mul(&db.Flag.Float64, &db.Flag.Float64))).
+					Where(where.Equals(&db.Flag.Id, f.Id))
+				if err != nil {
+					t.Fatalf("Expected a update, got error: %v", err)
+				}
+
+				var fselect *Flag
+				fselect, err = goe.Find(db.Flag).ByValue(Flag{Id: f.Id})
+				if err != nil {
+					t.Fatalf("Expected a select, got error: %v", err)
+				}
+				if fselect.Int != 11 {
+					t.Errorf("Expected 11, got : %v", fselect.Int)
+				}
+				if fselect.Float64 != 6.25 {
+					t.Errorf("Expected 6.25, got : %v", fselect.Float64)
+				}
+			},
+		},
+		{
+			desc: "Update_Expression_Other_Table",
+			testCase: func(t *testing.T) {
+				err = goe.Update(db.Flag).
+					Sets(update.Expression(&db.Flag.Int, expr.Add(&db.Animal.Id, expr.Argument(10)))).
+					Where(where.Equals(&db.Flag.Name, "Flag_Expression"))
+				if err == nil {
+					t.Error("Expected a error on a expression of other table, got nil")
+				}
+			},
+		},
 		{
 			desc: "Update_Context_Cancel",
 			testCase: func(t *testing.T) {
@@ -2,6 +2,8 @@ package goe
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/go-goe/goe/enum"
@@ -65,7 +67,7 @@ func (s save[T]) OnTransaction(tx model.Transaction) save[T] {
 }
 
 func (s save[T]) One(v T) error {
-	argsSave := getArgsSave(addrMap.mapField, s.table, v)
+	argsSave := getArgsSave(loadFields(), s.table, v)
 	// skip queries on empty models
 	if argsSave.skip {
 		return nil
@@ -79,6 +81,7 @@ type stateUpdate[T any] struct {
 	conn    model.Connection
 	builder builder
 	ctx     context.Context
+	err     error // error of the sets, returned by Where
 }
 
 // Update updates records in the given table.
@@ -117,7 +120,16 @@ func UpdateContext[T any](ctx context.Context, table *T) stateUpdate[T] {
 // Sets one or more arguments for update
 func (s stateUpdate[T]) Sets(sets ...model.Set) stateUpdate[T] {
 	for i := range sets {
-		s.builder.sets = append(s.builder.sets, set{attribute: getArg(sets[i].Attribute, addrMap.mapField, nil), value: sets[i].Value})
+		if e, ok := sets[i].Value.(model.Expressioner); ok {
+			attribute := getArg(sets[i].Attribute, loadFields(), nil)
+			expression, column := buildExpression(loadFields(), e)
+			if column == nil || column.getTableId() != attribute.getTableId() {
+				s.err = errors.Join(s.err, fmt.Errorf("goe: invalid expression on set of %v.%v. try using the fields of the updated table", attribute.table(), attribute.getAttributeName()))
+			}
+			s.builder.sets = append(s.builder.sets, set{attribute: attribute, expression: expression})
+			continue
+		}
+		s.builder.sets = append(s.builder.sets, set{attribute: getArg(sets[i].Attribute, loadFields(), nil), value: sets[i].Value})
 	}
 
 	return s
@@ -156,12 +168,18 @@ func (s stateUpdate[T]) All() error {
 
 // Where receives [model.Where] as where operations from where sub package
 func (s stateUpdate[T]) Where(o model.Where) error {
+	if s.err != nil {
+		return s.err
+	}
 	s.builder.buildSets()
-	helperWhere(&s.builder, addrMap.mapField, &o)
+	helperWhere(&s.builder, loadFields(), &o)
 	s.builder.query.Where = &o
 	s.builder.buildUpdate()
 
 	driver := s.builder.sets[0].attribute.getDb().driver
+	if err := checkQuery(driver, &s.builder.query); err != nil {
+		return err
+	}
 	if s.conn == nil {
 		s.conn = driver.NewConnection()
 	}
@@ -176,7 +194,7 @@ type argSave struct {
 	skip        bool
 }
 
-func getArgsSave[T any](addrMap map[uintptr]field, table *T, value T) argSave {
+func getArgsSave[T any](addrMap databases, table *T, value T) argSave {
 	if table == nil {
 		panic("goe: invalid argument. try sending a pointer to a database mapped struct as argument")
 	}
@@ -192,21 +210,22 @@ func getArgsSave[T any](addrMap map[uintptr]field, table *T, value T) argSave {
 	sets := make([]set, 0)
 	pksWhere, valuesWhere := make([]any, 0, valueOf.NumField()), make([]any, 0, valueOf.NumField())
 
-	var addr uintptr
-	for i := 0; i < valueOf.NumField(); i++ {
-		if !valueOf.Field(i).IsZero() {
-			addr = uintptr(tableOf.Field(i).Addr().UnsafePointer())
-			if addrMap[addr] != nil {
-				if addrMap[addr].isPrimaryKey() {
-					pksWhere = append(pksWhere, tableOf.Field(i).Addr().Interface())
-					valuesWhere = append(valuesWhere, valueOf.Field(i).Interface())
+	var fieldOf reflect.Value
+	for _, field := range structFields(valueOf.Type()) {
+		fieldOf = valueOf.FieldByIndex(field.Index)
+		if !isIgnored(field) && !fieldOf.IsZero() {
+			if f := addrMap.field(uintptr(tableOf.FieldByIndex(field.Index).Addr().UnsafePointer())); f != nil {
+				if f.isPrimaryKey() {
+					pksWhere = append(pksWhere, tableOf.FieldByIndex(field.Index).Addr().Interface())
+					valuesWhere = append(valuesWhere, fieldOf.Interface())
 					continue
 				}
-				sets = append(sets, set{attribute: addrMap[addr], value: valueOf.Field(i).Interface()})
+				sets = append(sets, set{attribute: f, value: fieldOf.Interface()})
 			}
 		}
 	}
-	if len(pksWhere) == 0 || len(valuesWhere) == 0 {
+	// without sets there is nothing to update, a value with only the primary keys and ignored fields is skipped
+	if len(pksWhere) == 0 || len(valuesWhere) == 0 || len(sets) == 0 {
 		return argSave{skip: true}
 	}
 	return argSave{sets: sets, argsWhere: pksWhere, valuesWhere: valuesWhere}
@@ -52,3 +52,26 @@ func namePattern(name string) string {
 	}
 	return strings.ToLower(result.String())
 }
+
+// DefaultNamingStrategy is the [model.NamingStrategy] used when none is set on the database config
+type DefaultNamingStrategy struct{}
+
+// TableName uses the [TableNamePattern]
+func (DefaultNamingStrategy) TableName(name string) string {
+	return TableNamePattern(name)
+}
+
+// ColumnName uses the [ColumnNamePattern]
+func (DefaultNamingStrategy) ColumnName(name string) string {
+	return ColumnNamePattern(name)
+}
+
+// IndexName returns the pattern table_idx_field
+func (DefaultNamingStrategy) IndexName(table, field string) string {
+	return table + "_idx_" + strings.ToLower(field)
+}
+
+// ForeignKeyName returns the pattern table_column_fkey
+func (DefaultNamingStrategy) ForeignKeyName(table, column string) string {
+	return table + "_" + column + "_fkey"
+}
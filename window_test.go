@@ -0,0 +1,54 @@
+package goe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-goe/goe/enum"
+	"github.com/go-goe/goe/query/window"
+)
+
+func TestSelectWindow(t *testing.T) {
+	db, d := openTest(t, false)
+
+	_, err := Select[struct {
+		Name     string
+		Number   int64
+		Previous *int
+	}](&db.Animal.Name,
+		window.RowNumber().PartitionBy(&db.Animal.HabitatId).OrderByDesc(&db.Animal.Age),
+		window.Lag(&db.Animal.Age, 1).OrderByAsc(&db.Animal.Name)).AsSlice()
+	if err != nil {
+		t.Fatalf("Expected window, got error %v", err)
+	}
+
+	q := d.lastQuery(t)
+	if len(q.Attributes) != 3 {
+		t.Fatalf("Expected 3 attributes, got %+v", q.Attributes)
+	}
+	number := q.Attributes[1].Window
+	if number == nil || number.Type != enum.RowNumberWindow {
+		t.Fatalf("Expected row number, got %+v", q.Attributes[1])
+	}
+	if len(number.PartitionBy) != 1 || number.PartitionBy[0].Name != `"habitat_id"` {
+		t.Errorf("Expected the partition by habitat id, got %+v", number.PartitionBy)
+	}
+	if len(number.OrderBy) != 1 || !number.OrderBy[0].Desc || number.OrderBy[0].Attribute.Name != `"age"` {
+		t.Errorf("Expected the order by age desc, got %+v", number.OrderBy)
+	}
+	if previous := q.Attributes[2]; previous.Window == nil || previous.Window.Type != enum.LagWindow || previous.Window.Offset != 1 || previous.Name != `"age"` {
+		t.Errorf("Expected lag of age by 1, got %+v", previous)
+	}
+}
+
+func TestSelectWindowBaseline(t *testing.T) {
+	db, _ := openTest(t, true)
+
+	_, err := Select[struct {
+		Name string
+		Rank int64
+	}](&db.Animal.Name, window.Rank().OrderByDesc(&db.Animal.Age)).AsSlice()
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}